@@ -14,13 +14,30 @@ var AddCmd = &cobra.Command{
 	Short:   "Add a new path to goto-paths file",
 	Long:    `To use the add-path command you need to pass two args: a path and an abbreviation to create a new goto-path`,
 	Example: `
-# Format: goto add-path [ -t ] path abbv
+# Format: goto add-path [ -t [ --ttl duration ] [ --session ] | -l ] path abbv [ --tag tag ]... [ --description text ]
 
 # This command add the current directory to the gpaths file with the abbreviation "currentDir"
 goto add-path ./ currentDir
 
 # To specify the path and abbreviation use:
 goto add-path ~/Documents docs
+
+# To add tags and a description use:
+goto add-path ~/work/acme/api acme-api --tag acme --tag api --description "ACME billing API"
+goto add-path ~/work/acme/web acme-web --tag acme,web
+
+# To add a path to the project file of the repository (.goto.json, the path is saved relative to the file):
+goto add-path --local ./docs docs
+
+# To save the path relative to the home ("~/projects/api") or to an environment variable ("$WORK/api"),
+# so the goto-paths file works in other machines (the path is expanded when it is used):
+goto add-path --portable ~/projects/api api
+goto add-path --portable --portable-var WORK $WORK/api work-api
+goto add-path '~/projects/web' web
+
+# To add a temporal path that expires after 2 hours and/or is only seen from this shell:
+goto add-path -t --ttl 2h /tmp/build build
+goto add-path -t --session ./ here
 `,
 	Args: cobra.ExactArgs(2),
 
@@ -28,10 +45,38 @@ goto add-path ~/Documents docs
 }
 
 func runAdd(cmd *cobra.Command, args []string) {
-	cobra.CheckErr(core.AddPath(args[0], args[1], utils.TemporalFlagPassed(cmd)))
+	tags, _ := cmd.Flags().GetStringSlice(utils.FlagTag)
+	description, _ := cmd.Flags().GetString(utils.FlagDescription)
+	local, _ := cmd.Flags().GetBool("local")
+	portable, _ := cmd.Flags().GetBool("portable")
+	portableVars, _ := cmd.Flags().GetStringSlice("portable-var")
+	ttl, _ := cmd.Flags().GetDuration("ttl")
+	session, _ := cmd.Flags().GetBool("session")
+
+	cobra.CheckErr(core.AddPathWithOptions(args[0], args[1], core.AddOptions{
+		Tags:        tags,
+		Description: description,
+		Local:       local,
+
+		//A portable variable implies the portable flag
+		Portable:     portable || len(portableVars) > 0,
+		PortableVars: portableVars,
+
+		TTL:     ttl,
+		Session: session,
+	}, utils.TemporalFlagPassed(cmd)))
 }
 
 func init() {
 	//Add this command to RootCmd
 	RootCmd.AddCommand(AddCmd)
+
+	//Flags
+	AddCmd.Flags().StringSlice(utils.FlagTag, nil, "A tag of the Path (can be repeated or separated by commas)")
+	AddCmd.Flags().String(utils.FlagDescription, "", "A description of the Path")
+	AddCmd.Flags().BoolP("local", "l", false, "Add the Path to the nearest project file (.goto.json), it's created in the current directory if there isn't any")
+	AddCmd.Flags().Bool("portable", false, "Save the Path relative to the home (\"~/...\") so it works in other machines")
+	AddCmd.Flags().StringSlice("portable-var", nil, "Save the Path relative to this environment variable (\"$VAR/...\") if it is inside it (implies --portable)")
+	AddCmd.Flags().Duration("ttl", 0, "Remove the temporal Path after this time (e.g. 30m, 2h), only with -t")
+	AddCmd.Flags().Bool("session", false, "Only show the temporal Path in the current shell session, only with -t")
 }
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"goto/src/core"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// BackCmd represents the back command
+var BackCmd = &cobra.Command{
+	Use:   "back [n]",
+	Short: "Go back n directories (by default, 1) in the directory stack of the shell session (exit with status 2)",
+	Long: `Go back n directories (by default, 1) in the directory stack of the shell session. The stack keeps the
+directories visited with goto in this shell (the shell integration passes its PID in GOTO_SESSION).`,
+	Example: `
+# Format: goto back [ n ]
+
+# Go to the previous directory
+goto back
+
+# Go back 3 directories
+goto back 3
+
+# Then, go forward again
+goto forward
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n := getStackSteps(args)
+		path, err := core.MoveStack(-n, navigates(cmd))
+		cobra.CheckErr(err)
+
+		exitWithPath(cmd, path)
+	},
+}
+
+// getStackSteps returns the number of directories to move in the stack (by default, 1)
+func getStackSteps(args []string) int {
+	if len(args) == 0 {
+		return 1
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 {
+		cobra.CheckErr(fmt.Errorf("the number of directories \"%s\" must be a positive number", args[0]))
+	}
+	return n
+}
+
+func init() {
+	RootCmd.AddCommand(BackCmd)
+}
@@ -2,8 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"goto/src/config"
 	"goto/src/core"
+	"goto/src/gpath"
 	"goto/src/utils"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -13,13 +19,19 @@ var BackupCmd = &cobra.Command{
 	Use:   "backup",
 	Short: "Do a backup of the goto-paths file",
 	Example: `
-# Format: goto backup [ -o path ]
+# Format: goto backup [ -f path ]
 
 # Made a backup of goto-paths in the config directory
 goto backup
 
-# If you want to specify the output path
-goto backup -o /the/path/file.json.backup
+# If you want to specify the destination path (before it was -o/--output, that still works but it is deprecated)
+goto backup -f /the/path/file.json.backup
+
+# To get the backed up goto-paths in a machine-readable format
+goto backup -f /the/path/file.json.backup -o json
+
+# An automatic backup is saved before each change of the goto-paths file, to list them use:
+goto backup list
 `,
 	Args: cobra.ExactArgs(0),
 	Run:  runBackup,
@@ -27,17 +39,140 @@ goto backup -o /the/path/file.json.backup
 
 func runBackup(cmd *cobra.Command, _ []string) {
 
-	//Get output flag (if is not passed, have already a default value)
-	output, err := cmd.Flags().GetString("output")
+	//Get file flag (if is not passed, use the default backup of the profile in use)
+	output, err := cmd.Flags().GetString("file")
 	cobra.CheckErr(err)
 
+	if !utils.FlagPassed(cmd, "file") {
+		output = utils.GetDefaultBackupFilePath()
+	}
+
+	//Before the global output flag, -o/--output was the destination path: a value that isn't an
+	//output format is still used as the path (deprecated, use -f/--file)
+	if path, ok := legacyBackupPath(cmd); ok {
+		fmt.Fprintf(os.Stderr, "Warning: using -o/--output as the backup destination is deprecated, use -f/--file\n")
+		output = path
+		cobra.CheckErr(cmd.Flags().Set(utils.FlagOutput, utils.OutputText))
+	}
+
 	cobra.CheckErr(core.BackupGPaths(output, utils.TemporalFlagPassed(cmd)))
+
+	//If the output flag is passed, print the backed up gpaths in that format
+	if utils.GetOutputFormat(cmd) != utils.OutputText {
+		gpaths, err := core.ListPaths(utils.TemporalFlagPassed(cmd))
+		cobra.CheckErr(err)
+
+		entries := make([]utils.OutputEntry, 0, len(gpaths))
+		for i := range gpaths {
+			entries = append(entries, utils.NewOutputEntry(i, gpaths[i]))
+		}
+		printStructured(cmd, entries)
+		return
+	}
+
 	fmt.Printf("Backup complete from %s\n", utils.GetFilePath(utils.TemporalFlagPassed(cmd)))
 }
 
+// BackupListCmd represents the backup list command
+var BackupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the automatic backups of the goto-paths file",
+	Long: `List the automatic backups of the goto-paths file, the newest first.
+A backup is saved before each change of the goto-paths file and the last ` + strconv.Itoa(config.DefaultBackupGenerations) + ` are kept
+(change it with "goto config set backup_generations", 0 disables them).`,
+	Example: `
+# Format: goto backup list
+
+# List the automatic backups
+goto backup list
+
+# List them in a machine-readable format
+goto backup list -o json
+
+# Show the goto-paths of the newest backup and restore it
+goto backup show 0
+goto restore --generation 0
+`,
+	Args: cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		generations, err := core.ListBackupGenerations()
+		cobra.CheckErr(err)
+
+		//If the output flag is passed, print the backups in that format (the path is the backup file)
+		entries := make([]utils.OutputEntry, 0, len(generations))
+		for i, g := range generations {
+			entry := utils.NewOutputEntry(i, gpath.GotoPath{Path: g.File})
+			entry.SavedAt = g.Time.Format(time.RFC3339)
+			entries = append(entries, entry)
+		}
+		if printStructured(cmd, entries) {
+			return
+		}
+
+		if len(generations) == 0 {
+			fmt.Println("There are no automatic backups")
+			return
+		}
+
+		for i, g := range generations {
+			fmt.Printf("%v - %s (%s)\n", i, g.Time.Local().Format(time.DateTime), g.File)
+		}
+	},
+}
+
+// BackupShowCmd represents the backup show command
+var BackupShowCmd = &cobra.Command{
+	Use:   "show generation",
+	Short: "Show the goto-paths of an automatic backup",
+	Example: `
+# Format: goto backup show generation
+
+# Show the goto-paths of the newest automatic backup
+goto backup show 0
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			cobra.CheckErr(fmt.Errorf("the generation must be a number"))
+		}
+
+		gpaths, err := core.LoadBackupGeneration(n)
+		cobra.CheckErr(err)
+
+		//If the output flag is passed, print the gpaths in that format
+		entries := make([]utils.OutputEntry, 0, len(gpaths))
+		for i := range gpaths {
+			entries = append(entries, utils.NewOutputEntry(i, gpaths[i]))
+		}
+		if printStructured(cmd, entries) {
+			return
+		}
+
+		for i := range gpaths {
+			fmt.Printf("%v - %s\n", i, gpaths[i].String())
+		}
+	},
+}
+
+// legacyBackupPath returns the value of the output flag if it is a destination path (it isn't an
+// output format or a template) and the file flag is not passed
+func legacyBackupPath(cmd *cobra.Command) (string, bool) {
+	if !utils.FlagPassed(cmd, utils.FlagOutput) || utils.FlagPassed(cmd, "file") {
+		return "", false
+	}
+
+	value, _ := cmd.Flags().GetString(utils.FlagOutput)
+	if utils.ValidOutputFormat(value) == nil || strings.Contains(value, "{{") {
+		return "", false
+	}
+	return value, true
+}
+
 func init() {
 	RootCmd.AddCommand(BackupCmd)
+	BackupCmd.AddCommand(BackupListCmd, BackupShowCmd)
 
 	//Flags
-	BackupCmd.Flags().StringP("output", "o", utils.GetDefaultBackupFilePath(), "The backup destination path (must be a file path)")
+	BackupCmd.Flags().StringP("file", "f", utils.GetDefaultBackupFilePath(), "The backup destination path (must be a file path)")
 }
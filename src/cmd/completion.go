@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// loadCompletionGPaths loads the gpaths to complete. The profile is selected here because
+// the completion doesn't run the PersistentPreRun of the root command.
+func loadCompletionGPaths(cmd *cobra.Command, resolvable bool) []gpath.GotoPath {
+	profile, _ := cmd.Flags().GetString(utils.FlagProfile)
+	if utils.UseProfile(profile) != nil {
+		return nil
+	}
+
+	var gpaths []gpath.GotoPath
+	var err error
+	if resolvable {
+		gpaths, err = core.ListResolvablePaths(utils.TemporalFlagPassed(cmd))
+	} else {
+		gpaths, err = core.ListPaths(utils.TemporalFlagPassed(cmd))
+	}
+	if err != nil {
+		return nil
+	}
+	return gpaths
+}
+
+// completeRoot completes the argument of goto: the abbreviations, the indexes (with the path
+// as description) and then the directories
+func completeRoot(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	gpaths := loadCompletionGPaths(cmd, true)
+	completions := append(abbreviationCompletions(layeredCompletionGPaths(cmd, gpaths), toComplete), indexCompletions(gpaths, toComplete)...)
+	completions = append(completions, directoryCompletions(toComplete)...)
+
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveKeepOrder
+}
+
+// layeredCompletionGPaths returns the gpaths whose abbreviations are resolved without the temporal flag
+// (the ones of all the layers), or the gpaths if the temporal flag is passed or the layers can't be loaded
+func layeredCompletionGPaths(cmd *cobra.Command, gpaths []gpath.GotoPath) []gpath.GotoPath {
+	if utils.TemporalFlagPassed(cmd) {
+		return gpaths
+	}
+
+	layers, err := core.ListLayers()
+	if err != nil {
+		return gpaths
+	}
+
+	layered := []gpath.GotoPath{}
+	for _, l := range layers {
+		if l.ShadowedBy == "" {
+			layered = append(layered, l.GotoPath)
+		}
+	}
+	return layered
+}
+
+// registerGPathFlagsCompletion completes the flags that identify a gpath (--path, --abbv and --indx)
+// with the gpaths of the goto-paths file
+func registerGPathFlagsCompletion(cmd *cobra.Command) {
+	complete := map[string]func(gpaths []gpath.GotoPath, toComplete string) []string{
+		utils.FlagPath:         pathCompletions,
+		utils.FlagAbbreviation: abbreviationCompletions,
+		utils.FlagIndex:        indexCompletions,
+	}
+
+	for flag, f := range complete {
+		if cmd.Flags().Lookup(flag) == nil {
+			continue
+		}
+		_ = cmd.RegisterFlagCompletionFunc(flag, func(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return f(loadCompletionGPaths(cmd, false), toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+		})
+	}
+}
+
+// abbreviationCompletions returns the abbreviations that start with toComplete (with the path as description)
+func abbreviationCompletions(gpaths []gpath.GotoPath, toComplete string) []string {
+	completions := []string{}
+	for _, gp := range gpaths {
+		if strings.HasPrefix(gp.Abbreviation, toComplete) {
+			completions = append(completions, gp.Abbreviation+"\t"+gp.Path)
+		}
+	}
+	return completions
+}
+
+// indexCompletions returns the indexes that start with toComplete (with the path as description)
+func indexCompletions(gpaths []gpath.GotoPath, toComplete string) []string {
+	completions := []string{}
+	for i, gp := range gpaths {
+		if index := strconv.Itoa(i); strings.HasPrefix(index, toComplete) {
+			completions = append(completions, index+"\t"+gp.Path)
+		}
+	}
+	return completions
+}
+
+// pathCompletions returns the paths that start with toComplete (with the abbreviation as description)
+func pathCompletions(gpaths []gpath.GotoPath, toComplete string) []string {
+	completions := []string{}
+	for _, gp := range gpaths {
+		if path := gp.ExpandedPath(); strings.HasPrefix(path, toComplete) {
+			completions = append(completions, path+"\t"+gp.Abbreviation)
+		}
+	}
+	return completions
+}
+
+// directoryCompletions returns the directories that start with toComplete (ending with a separator,
+// to continue completing inside them)
+func directoryCompletions(toComplete string) []string {
+	dir, prefix := filepath.Split(toComplete)
+
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	} else if strings.HasPrefix(readDir, "~") {
+		if home, err := os.UserHomeDir(); err == nil {
+			readDir = home + readDir[1:]
+		}
+	}
+
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return []string{}
+	}
+
+	completions := []string{}
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, prefix) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".")) {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(readDir, name)); err == nil && info.IsDir() {
+			completions = append(completions, dir+name+string(filepath.Separator))
+		}
+	}
+	return completions
+}
+
+// completeUpdateModes completes the modes of update-path (with what each one does as description)
+func completeUpdateModes(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := map[string]string{"path": "path", "abbv": "abbreviation", "indx": "index", "tags": "tags", "desc": "description"}
+
+	completions := []string{}
+	for _, mode := range updateModes {
+		identifier, target, _ := strings.Cut(mode[0], "-")
+		description := "identify by " + names[identifier] + ", update the " + names[target]
+		for _, m := range mode {
+			if strings.HasPrefix(m, toComplete) {
+				completions = append(completions, m+"\t"+description)
+			}
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeGPathsOf returns a completion of the abbreviations and the indexes of the goto-paths file
+// or the temporal file (e.g. for promote and demote, that don't use the temporal flag)
+func completeGPathsOf(temporal bool) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		profile, _ := cmd.Flags().GetString(utils.FlagProfile)
+		if utils.UseProfile(profile) != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		gpaths, err := core.ListPaths(temporal)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return append(abbreviationCompletions(gpaths, toComplete), indexCompletions(gpaths, toComplete)...), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	}
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"goto/src/config"
+	"goto/src/core"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// ConfigCmd represents the config command
+var ConfigCmd = &cobra.Command{
+	Use:     "config",
+	Aliases: []string{"settings"},
+	Short:   "Manage the settings of goto",
+	Long: `
+The settings of goto are stored in the config.json file of the config directory (e.g. ~/.config/goto/config.json),
+so they can be shared in the dotfiles. Each setting can be overridden with an environment variable
+(GOTO_ and the name of the setting in uppercase, e.g. GOTO_NAVIGATE_MESSAGE).
+
+Run "goto init" after changing navigate_exit_code or navigate_message, they are written in the shell scripts.
+
+Without a subcommand, "goto config" keeps moving to the gpath with the "config" abbreviation
+(by default, the config directory).
+`,
+	Example: `
+# Format: goto config [ list | get key | set key [ value ] | edit ]
+
+# Move to the config directory (the default "config" abbreviation)
+goto config
+
+# Print it like "goto -q config" or "goto -o json config"
+goto config -q
+goto config -o json
+
+# List all the settings with their values and where they come from (env, file or default)
+goto config list
+
+# Don't print the message after moving to a path
+goto config set navigate_message none
+
+# Restore the default value of a setting
+goto config set navigate_message
+
+# Open the config file with $EDITOR
+goto config edit
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		//"config" is also a default abbreviation, so "goto config" resolves it like before this command existed
+		runRoot(cmd, []string{cmd.Name()})
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List all the settings with their values and where they come from",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		settings, err := core.ListSettings()
+		cobra.CheckErr(err)
+
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		for _, s := range settings {
+			fmt.Printf("%s = %s (%s)\n", s.Name, s.Value, s.Source)
+			if verbose {
+				fmt.Printf("    %s [%s]\n", s.Description, s.EnvVar())
+			}
+		}
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:               "get key",
+	Short:             "Print the value in use of a setting",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSettingKeys,
+	Run: func(cmd *cobra.Command, args []string) {
+		setting, err := core.GetSetting(args[0])
+		cobra.CheckErr(err)
+
+		fmt.Println(setting.Value)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:               "set key [value]",
+	Short:             "Change a setting in the config file (without value, its default value is restored)",
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeSettingKeys,
+	Run: func(cmd *cobra.Command, args []string) {
+		value := ""
+		if len(args) == 2 {
+			value = args[1]
+		}
+
+		warning, err := core.SetSetting(args[0], value)
+		cobra.CheckErr(err)
+
+		if warning != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+		if value == "" {
+			fmt.Printf("The setting %s was restored to its default value\n", args[0])
+		} else {
+			fmt.Printf("The setting %s was changed to %s\n", args[0], value)
+		}
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file with the editor of $EDITOR and validate it",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(core.EditConfigFile())
+		fmt.Printf("The config file %s is valid\n", config.GetFilePath())
+	},
+}
+
+// completeSettingKeys completes the names of the settings (only the first argument)
+func completeSettingKeys(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := []cobra.Completion{}
+	for _, key := range config.Keys() {
+		completions = append(completions, cobra.CompletionWithDesc(key.Name, key.Description))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	RootCmd.AddCommand(ConfigCmd)
+	ConfigCmd.AddCommand(configListCmd, configGetCmd, configSetCmd, configEditCmd)
+
+	//Flags (without a subcommand, "goto config" navigates like the root command, so it has its flags)
+	addNavigationFlags(ConfigCmd)
+	configListCmd.Flags().BoolP("verbose", "v", false, "Print the description and the environment variable of each setting")
+}
@@ -87,4 +87,5 @@ func init() {
 	DeleteCmd.Flags().StringP(utils.FlagPath, "p", "", "The Path to delete")
 	DeleteCmd.Flags().StringP(utils.FlagAbbreviation, "a", "", "The Abbreviation of the Path")
 	DeleteCmd.Flags().IntP(utils.FlagIndex, "i", -1, "The Index of the Path")
+	registerGPathFlagsCompletion(DeleteCmd)
 }
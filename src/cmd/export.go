@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"goto/src/core"
+	"goto/src/utils"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// ExportCmd represents the export command
+var ExportCmd = &cobra.Command{
+	Use:   "export format",
+	Short: "Export the goto-paths file to the bookmark formats of shells and other tools",
+	Long: `Export the goto-paths file to the bookmark formats of shells and other tools.
+The paths are sorted by abbreviation, so the output only changes when the goto-paths change
+and it can be checked into a dotfiles repository.`,
+	Example: `
+# Format: goto export [ -t ] format [ -f file ]
+
+# Print the goto-paths as zsh named directories (cd ~docs)
+goto export zsh
+
+# Write the goto-paths as fish abbreviations
+goto export fish -f ~/.config/fish/conf.d/goto-abbr.fish
+
+# Write a .sdirs file for bashmarks
+goto export bashmarks -f ~/.sdirs
+
+# Import the goto-paths in zoxide
+goto export zoxide -f /tmp/goto.z && zoxide import --from z /tmp/goto.z --merge
+`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: core.ExporterNames(),
+	Run:       runExport,
+}
+
+func runExport(cmd *cobra.Command, args []string) {
+	useTemporal := utils.TemporalFlagPassed(cmd)
+
+	//If the file flag is not passed, print the export
+	if !utils.FlagPassed(cmd, "file") {
+		cobra.CheckErr(core.ExportGPaths(os.Stdout, args[0], useTemporal))
+		return
+	}
+
+	file, err := cmd.Flags().GetString("file")
+	cobra.CheckErr(err)
+
+	cobra.CheckErr(core.ExportGPathsToFile(file, args[0], useTemporal))
+
+	fmt.Printf("Export complete in %s\n", file)
+}
+
+func init() {
+	RootCmd.AddCommand(ExportCmd)
+
+	//Flags
+	ExportCmd.Flags().StringP("file", "f", "", "The file where the export is written (overwritten if it exists)")
+
+	//List the formats in the help
+	descriptions := []string{}
+	for _, name := range core.ExporterNames() {
+		exporter, _ := core.GetExporter(name)
+		descriptions = append(descriptions, fmt.Sprintf("  %s: %s", name, exporter.Description))
+	}
+	ExportCmd.Long += "\n\nFormats:\n" + strings.Join(descriptions, "\n")
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"goto/src/core"
+
+	"github.com/spf13/cobra"
+)
+
+// ForwardCmd represents the forward command
+var ForwardCmd = &cobra.Command{
+	Use:   "forward [n]",
+	Short: "Go forward n directories (by default, 1) in the directory stack of the shell session (exit with status 2)",
+	Example: `
+# Format: goto forward [ n ]
+
+# Undo the last "goto back"
+goto forward
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n := getStackSteps(args)
+		path, err := core.MoveStack(n, navigates(cmd))
+		cobra.CheckErr(err)
+
+		exitWithPath(cmd, path)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(ForwardCmd)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"goto/src/core"
+	"goto/src/utils"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// HistoryCmd represents the history command
+var HistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List the changes of the goto-paths file that can be undone or redone",
+	Example: `
+# Format: goto history [ -t ]
+
+# List the changes, the undone ones are marked with "(undone)"
+goto history
+`,
+	Args: cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		items, err := core.History(utils.TemporalFlagPassed(cmd))
+		cobra.CheckErr(err)
+
+		if len(items) == 0 {
+			fmt.Println("There are no changes")
+			return
+		}
+
+		for i, item := range items {
+			undone := ""
+			if item.Undone {
+				undone = " (undone)"
+			}
+
+			fmt.Printf("%v - %s %s [+%v -%v ~%v]%s\n", i, item.Time.Format(time.DateTime), item.Operation,
+				len(item.Diff.Added), len(item.Diff.Removed), len(item.Diff.Changed), undone)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(HistoryCmd)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"goto/src/core"
+	"goto/src/utils"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// ImportCmd represents the import command
+var ImportCmd = &cobra.Command{
+	Use:   "import importer",
+	Short: "Import the bookmarks of other jump tools to the goto-paths file",
+	Long: `Import the bookmarks of other jump tools (zoxide, autojump, z, fasd or bashmarks) to the goto-paths file.
+The paths that don't exist or that are already in the goto-paths file are skipped, and an abbreviation is
+generated (from the name of the directory) for the paths without one or with an abbreviation already used.
+The conflicts with the existing goto-paths are reported before writing the file.`,
+	Example: `
+# Format: goto import [ -t ] importer [ -f file ] [ --dry-run ]
+
+# Import the database of zoxide (read from stdin)
+zoxide query -ls | goto import zoxide
+
+# Import the default data file of autojump, z, fasd or bashmarks
+goto import autojump
+goto import z
+goto import fasd
+goto import bashmarks
+
+# Import a specific file
+goto import bashmarks -f ~/dotfiles/sdirs
+
+# Show what would be imported without writing the goto-paths file
+goto import z --dry-run
+`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: core.ImporterNames(),
+	Run:       runImport,
+}
+
+func runImport(cmd *cobra.Command, args []string) {
+	file, err := cmd.Flags().GetString("file")
+	cobra.CheckErr(err)
+
+	useTemporal := utils.TemporalFlagPassed(cmd)
+	plan, err := core.PlanImport(args[0], file, useTemporal)
+	cobra.CheckErr(err)
+
+	//Report the conflicts and the invalid paths before writing
+	for _, c := range plan.Conflicts {
+		fmt.Printf("Conflict: \"%s\" %s\n", c.Path, c.Reason)
+	}
+	for _, s := range plan.Skipped {
+		fmt.Printf("Skipped: %s\n", s.Reason)
+	}
+
+	if utils.FlagPassed(cmd, "dry-run") {
+		for _, gp := range plan.Added {
+			fmt.Printf("Would import: %s\n", gp.String())
+		}
+		fmt.Printf("%v paths would be imported\n", len(plan.Added))
+		return
+	}
+
+	imported, err := core.ApplyImport(plan, useTemporal)
+	cobra.CheckErr(err)
+
+	fmt.Printf("%v paths imported in %s\n", imported, utils.GetFilePath(useTemporal))
+}
+
+func init() {
+	RootCmd.AddCommand(ImportCmd)
+
+	//Flags
+	ImportCmd.Flags().StringP("file", "f", "", "The file to import, \"-\" is the stdin (default: the data file of the importer)")
+	ImportCmd.Flags().Bool("dry-run", false, "Show the paths that would be imported without writing the goto-paths file")
+
+	//List the importers in the help
+	descriptions := []string{}
+	for _, name := range core.ImporterNames() {
+		importer, _ := core.GetImporter(name)
+		descriptions = append(descriptions, fmt.Sprintf("  %s: %s", name, importer.Description))
+	}
+	ImportCmd.Long += "\n\nImporters:\n" + strings.Join(descriptions, "\n")
+}
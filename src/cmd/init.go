@@ -3,6 +3,9 @@ package cmd
 import (
 	"fmt"
 	"goto/src/core"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -11,22 +14,70 @@ import (
 var InitCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize goto configuration and shell alias",
-	Long:  `Initialize the .config directory, goto-path.json, and generate alias.sh. It also adds the alias to your shell configuration.`,
-	Args:  cobra.NoArgs,
-	Run:   runInit,
+	Long: `Initialize the .config directory, goto-path.json, and generate the script of your shell (alias.sh, alias.zsh,
+alias.fish, alias.csh or alias.ps1) and the completion scripts (bash, zsh and fish). It also adds the script to your
+shell configuration. The shell is detected from $SHELL or selected with the --shell flag.`,
+	Example: `
+# Initialize goto for the shell in $SHELL
+goto init
+
+# Initialize goto for other shell
+goto init --shell fish
+
+# Print the script of the shell without touching any file (add it to your rc file)
+eval "$(goto init --print zsh)"
+goto init --print fish | source
+Invoke-Expression (& goto init --print pwsh | Out-String)
+`,
+	Args: cobra.NoArgs,
+	Run:  runInit,
 }
 
 func runInit(cmd *cobra.Command, args []string) {
+
+	//If the print flag is passed, only print the script of the shell
+	if cmd.Flags().Changed("print") {
+		shell, _ := cmd.Flags().GetString("print")
+		cobra.CheckErr(core.PrintShellScript(os.Stdout, shell))
+		return
+	}
+
+	shell, _ := cmd.Flags().GetString("shell")
+
 	handler := core.NewMessageHandler(func(msg core.Message) {
 		fmt.Println(msg.Content)
 	})
 
-	err := core.InitializeConfig(handler.Channel())
+	err := core.InitializeConfig(handler.Channel(), core.InitOptions{Shell: shell, Completion: generateCompletion})
 	handler.CloseAndWait()
 
 	cobra.CheckErr(err)
 }
 
+// generateCompletion writes the completion script of goto for the shell
+func generateCompletion(shell string, w io.Writer) error {
+	switch shell {
+	case "bash":
+		return RootCmd.GenBashCompletionV2(w, true)
+	case "zsh":
+		return RootCmd.GenZshCompletion(w)
+	case "fish":
+		return RootCmd.GenFishCompletion(w, true)
+	default:
+		return fmt.Errorf("there is not completion for the shell \"%s\"", shell)
+	}
+}
+
+// completeShells completes the names of the supported shells
+func completeShells(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return core.ShellNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
 func init() {
 	RootCmd.AddCommand(InitCmd)
+	InitCmd.Flags().String("shell", "", "The shell to configure: "+strings.Join(core.ShellNames(), ", ")+" (by default, the shell in $SHELL)")
+	InitCmd.Flags().String("print", "", "Print the script of the shell to stdout, without touching any file")
+	InitCmd.MarkFlagsMutuallyExclusive("shell", "print")
+	_ = InitCmd.RegisterFlagCompletionFunc("shell", completeShells)
+	_ = InitCmd.RegisterFlagCompletionFunc("print", completeShells)
 }
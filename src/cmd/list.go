@@ -3,7 +3,9 @@ package cmd
 import (
 	"fmt"
 	"goto/src/core"
+	"goto/src/gpath"
 	"goto/src/utils"
+	"slices"
 
 	"github.com/spf13/cobra"
 )
@@ -14,34 +16,152 @@ var ListCmd = &cobra.Command{
 	Aliases: []string{"list"},
 	Short:   "List goto-paths in the goto-paths file",
 	Example: `
-# Format: goto list [ -t ] [ -R ]
+# Format: goto list [ -t | -A | -L ] [ -R ] [ --tag tag ]... [ --tag-mode and|or ]
 
 # List all gpaths
 goto list
 
 # List all gpaths form temporal file
 goto list -t
+
+# List the gpaths and the temporal gpaths (marked with [t])
+goto list --all
+
+# List the gpaths of all the layers in the order used to resolve the abbreviations (the first
+# one that has an abbreviation is used, the others are shadowed)
+goto list --layers
+
+# List the gpaths that have the tags "acme" and "api"
+goto list --tag acme --tag api
+
+# List the gpaths that have the tag "acme" or the tag "personal"
+goto list --tag acme,personal --tag-mode or
+
+# The gpaths of the project files (.goto.json) from the current directory upward
+# and the ones of the base profiles are listed after the gpaths of the file
+
+# List all gpaths as JSON (also ndjson, tsv or a Go template)
+goto list -o json
+goto list -o '{{.Abbreviation}} {{.Path}}'
 `,
 	Run: runList,
 }
 
+// listItem is a gpath to list, with its index in its file
+type listItem struct {
+	index    int
+	gpath    gpath.GotoPath
+	temporal bool
+}
+
 func runList(cmd *cobra.Command, _ []string) {
+	if utils.FlagPassed(cmd, "layers") {
+		runListLayers(cmd)
+		return
+	}
+
+	all := utils.FlagPassed(cmd, "all")
 
-	//Load the goto-paths file to array
-	gpaths, err := core.ListPaths(utils.TemporalFlagPassed(cmd))
+	//Load the goto-paths file to array (with --all, the temporal gpaths are listed after them)
+	gpaths, err := core.ListResolvablePaths(utils.TemporalFlagPassed(cmd) && !all)
 	cobra.CheckErr(err)
+	items := filterListItems(cmd, gpaths, false)
+
+	if all {
+		temporal, err := core.ListPaths(true)
+		cobra.CheckErr(err)
+		items = append(items, filterListItems(cmd, temporal, true)...)
+	}
 
 	if utils.FlagPassed(cmd, "reverse") { // If the reverse flag is passed
-		for i := range gpaths {
-			fmt.Printf("%v - %s\n", len(gpaths)-i-1, gpaths[len(gpaths)-i-1].String())
+		slices.Reverse(items)
+	}
+
+	//If the output flag is passed, print the gpaths in that format
+	entries := make([]utils.OutputEntry, 0, len(items))
+	for _, item := range items {
+		entry := utils.NewOutputEntry(item.index, item.gpath)
+		entry.Temporal = item.temporal
+		entries = append(entries, entry)
+	}
+	if printStructured(cmd, entries) {
+		return
+	}
+
+	for _, item := range items {
+		prefix := ""
+		if item.temporal { // The temporal gpaths are marked when both files are listed
+			prefix = "[t] "
+		}
+
+		if item.gpath.Source != "" { // The gpaths of project files or base profiles show where they come from
+			fmt.Printf("%s%v - %s (from %s)\n", prefix, item.index, item.gpath.String(), item.gpath.Source)
+			continue
 		}
+		fmt.Printf("%s%v - %s\n", prefix, item.index, item.gpath.String())
+	}
+}
+
+// runListLayers lists the gpaths of all the layers in the order used to resolve the abbreviations
+func runListLayers(cmd *cobra.Command) {
+	if utils.TemporalFlagPassed(cmd) {
+		cobra.CheckErr(fmt.Errorf("the layers flag lists all the layers, it can't be used with the temporal flag"))
+	}
+
+	layers, err := core.ListLayers()
+	cobra.CheckErr(err)
+
+	if utils.TagFlagPassed(cmd) { // If the tag flag is passed
+		tags, matchAll := utils.GetTagsFilter(cmd)
+		layers = slices.DeleteFunc(layers, func(l core.LayeredGPath) bool { return !l.HasTags(tags, matchAll) })
+	}
+
+	if utils.FlagPassed(cmd, "reverse") { // If the reverse flag is passed
+		slices.Reverse(layers)
+	}
+
+	//If the output flag is passed, print the gpaths in that format
+	entries := make([]utils.OutputEntry, 0, len(layers))
+	for _, l := range layers {
+		entries = append(entries, l.OutputEntry())
+	}
+	if printStructured(cmd, entries) {
 		return
 	}
 
-	//If any flag is passed
-	for i, gpath := range gpaths {
-		fmt.Printf("%v - %s\n", i, gpath.String())
+	for _, l := range layers {
+		line := fmt.Sprintf("[%s] %v - %s", l.Layer, l.Index, l.String())
+		if l.Index < 0 { // A project gpath that can't be used by index
+			line = fmt.Sprintf("[%s] - %s", l.Layer, l.String())
+		}
+
+		if l.Source != "" {
+			line += " (from " + l.Source + ")"
+		}
+		if l.ShadowedBy != "" {
+			line += " (shadowed by " + l.ShadowedBy + ")"
+		}
+		fmt.Println(line)
+	}
+}
+
+// filterListItems returns the gpaths to list (all of them or only the ones with the tags)
+func filterListItems(cmd *cobra.Command, gpaths []gpath.GotoPath, temporal bool) []listItem {
+	indexes := make([]int, len(gpaths))
+	for i := range gpaths {
+		indexes[i] = i
+	}
+
+	if utils.TagFlagPassed(cmd) { // If the tag flag is passed
+		tags, matchAll := utils.GetTagsFilter(cmd)
+		indexes = gpath.FilterByTags(gpaths, tags, matchAll)
+	}
+
+	items := make([]listItem, 0, len(indexes))
+	for _, idx := range indexes {
+		items = append(items, listItem{index: idx, gpath: gpaths[idx], temporal: temporal})
 	}
+	return items
 }
 
 func init() {
@@ -50,4 +170,8 @@ func init() {
 
 	//Flags
 	ListCmd.Flags().BoolP("reverse", "R", false, "List the goto-paths in reverse")
+	ListCmd.Flags().BoolP("all", "A", false, "List the goto-paths and the temporal goto-paths (marked with [t])")
+	ListCmd.Flags().BoolP("layers", "L", false, "List the goto-paths of all the layers (temporal, project and permanent) in the order used to resolve them")
+	ListCmd.MarkFlagsMutuallyExclusive("all", "layers")
+	utils.AddTagsFilterFlags(ListCmd)
 }
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"goto/src/utils"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// printStructured writes the entries in the format of the output flag.
+// If the output format is the text format, nothing is written and it returns false.
+func printStructured(cmd *cobra.Command, entries []utils.OutputEntry) bool {
+	format := utils.GetOutputFormat(cmd)
+	if format == utils.OutputText {
+		return false
+	}
+
+	cobra.CheckErr(utils.WriteOutput(os.Stdout, format, entries))
+	return true
+}
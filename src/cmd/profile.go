@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"goto/src/core"
+
+	"github.com/spf13/cobra"
+)
+
+// ProfileCmd represents the profile command
+var ProfileCmd = &cobra.Command{
+	Use:     "profile",
+	Aliases: []string{"profiles", "workspace"},
+	Short:   "Manage the profiles of goto-paths",
+	Long: `
+A profile is a separate goto-paths file (e.g. "work", "personal", "client-acme"). The profile in use
+is selected with the --profile flag, the GOTO_PROFILE environment variable or "goto profile switch"
+(in that order), otherwise the "default" profile (the goto-paths.json file) is used.
+
+A profile can have a base profile: the paths that are not in the profile are resolved in its base
+(and in the base of its base...), so a project profile can layer on top of a general one.
+`,
+	Example: `
+# Format: goto profile { list | create name [ --base profile ] | switch name | copy src dst | delete name }
+
+# List all the profiles (the profile in use is marked with "*")
+goto profile list
+
+# Create a profile that falls through the "work" profile
+goto profile create client-acme --base work
+
+# Use the profile in all the next commands
+goto profile switch client-acme
+
+# Use the profile only in one command
+goto --profile personal list
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List all the profiles",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		profiles, err := core.ListProfiles()
+		cobra.CheckErr(err)
+
+		for _, p := range profiles {
+			marker := " "
+			if p.Current {
+				marker = "*"
+			}
+
+			if p.Base != "" {
+				fmt.Printf("%s %s (base: %s)\n", marker, p.Name, p.Base)
+			} else {
+				fmt.Printf("%s %s\n", marker, p.Name)
+			}
+		}
+	},
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:     "create name",
+	Aliases: []string{"add", "new"},
+	Short:   "Create a new profile with the default goto-paths",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		base, _ := cmd.Flags().GetString("base")
+
+		cobra.CheckErr(core.CreateProfile(args[0], base))
+		fmt.Printf("The profile %s was created\n", args[0])
+	},
+}
+
+var profileSwitchCmd = &cobra.Command{
+	Use:     "switch name",
+	Aliases: []string{"use"},
+	Short:   "Select the profile to use by default",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(core.SwitchProfile(args[0]))
+		fmt.Printf("Switched to the profile %s\n", args[0])
+	},
+}
+
+var profileCopyCmd = &cobra.Command{
+	Use:     "copy src dst",
+	Aliases: []string{"cp"},
+	Short:   "Create a new profile with the goto-paths of other profile",
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(core.CopyProfile(args[0], args[1]))
+		fmt.Printf("The profile %s was copied to %s\n", args[0], args[1])
+	},
+}
+
+var profileDeleteCmd = &cobra.Command{
+	Use:     "delete name",
+	Aliases: []string{"del", "remove", "rm"},
+	Short:   "Delete a profile and its goto-paths",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(core.DeleteProfile(args[0]))
+		fmt.Printf("The profile %s was deleted\n", args[0])
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(ProfileCmd)
+	ProfileCmd.AddCommand(profileListCmd, profileCreateCmd, profileSwitchCmd, profileCopyCmd, profileDeleteCmd)
+
+	//Flags
+	profileCreateCmd.Flags().StringP("base", "b", "", "The profile where the paths that are not in the new profile are resolved")
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"goto/src/core"
+
+	"github.com/spf13/cobra"
+)
+
+// PromoteCmd represents the promote command
+var PromoteCmd = &cobra.Command{
+	Use:   "promote {abbreviation | index}",
+	Short: "Move a temporal goto-path to the goto-paths file",
+	Long: `Move a goto-path of the temporal file (identified by its abbreviation or index) to the goto-paths file.
+The goto-path doesn't expire anymore and it is seen from all the shell sessions.`,
+	Example: `
+# Format: goto promote { abbreviation | index }
+
+# Keep the temporal gpath "build"
+goto promote build
+
+# Keep the temporal gpath in the index 2
+goto promote 2
+`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeGPathsOf(true),
+	Run: func(_ *cobra.Command, args []string) {
+		promoted, err := core.PromotePath(args[0])
+		cobra.CheckErr(err)
+
+		fmt.Printf("The path %s (%s) was promoted to the goto-paths file\n", promoted.Path, promoted.Abbreviation)
+	},
+}
+
+// DemoteCmd represents the demote command
+var DemoteCmd = &cobra.Command{
+	Use:   "demote {abbreviation | index}",
+	Short: "Move a goto-path to the temporal file",
+	Long: `Move a goto-path of the goto-paths file (identified by its abbreviation or index) to the temporal file.
+It can expire after a time (--ttl) and be only seen from the current shell session (--session).`,
+	Example: `
+# Format: goto demote { abbreviation | index } [ --ttl duration ] [ --session ]
+
+# Move the gpath "old-project" to the temporal file
+goto demote old-project
+
+# Move the gpath in the index 3 to the temporal file, it is removed after a day
+goto demote 3 --ttl 24h
+`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeGPathsOf(false),
+	Run: func(cmd *cobra.Command, args []string) {
+		ttl, _ := cmd.Flags().GetDuration("ttl")
+		session, _ := cmd.Flags().GetBool("session")
+
+		demoted, err := core.DemotePath(args[0], ttl, session)
+		cobra.CheckErr(err)
+
+		fmt.Printf("The path %s (%s) was demoted to the temporal file\n", demoted.Path, demoted.Abbreviation)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(PromoteCmd)
+	RootCmd.AddCommand(DemoteCmd)
+
+	//Flags
+	DemoteCmd.Flags().Duration("ttl", 0, "Remove the temporal Path after this time (e.g. 30m, 2h)")
+	DemoteCmd.Flags().Bool("session", false, "Only show the temporal Path in the current shell session")
+}
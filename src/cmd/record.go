@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"goto/src/core"
+	"goto/src/gpath"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// RecordCmd represents the record command, used by the shell integration to record
+// the directories visited without goto (e.g. the chpwd hook of zsh)
+var RecordCmd = &cobra.Command{
+	Use:    "record [directory]",
+	Short:  "Record a visit of the directory (by default, the current directory) in the navigation history",
+	Hidden: true,
+	Args:   cobra.MaximumNArgs(1),
+	Run:    runRecord,
+}
+
+func runRecord(_ *cobra.Command, args []string) {
+	path, err := os.Getwd()
+	cobra.CheckErr(err)
+
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	cobra.CheckErr(gpath.ValidPathVar(&path))
+	cobra.CheckErr(core.RecordVisit(path))
+}
+
+func init() {
+	RootCmd.AddCommand(RecordCmd)
+}
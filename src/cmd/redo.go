@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"goto/src/core"
+	"goto/src/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// RedoCmd represents the redo command
+var RedoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Redo the last undone change of the goto-paths file",
+	Long: `Redo the last change of the goto-paths file undone with "goto undo".
+It fails if the goto-paths file was changed after the undo without goto.`,
+	Example: `
+# Format: goto redo [ -t ]
+
+# Redo the last undone change
+goto redo
+`,
+	Args: cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		operation, err := core.Redo(utils.TemporalFlagPassed(cmd))
+		cobra.CheckErr(err)
+
+		fmt.Printf("Redone: %s\n", operation)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(RedoCmd)
+}
@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"goto/src/core"
+	"goto/src/gpath"
 	"goto/src/utils"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -12,14 +17,34 @@ import (
 var RestoreCmd = &cobra.Command{
 	Use:   "restore",
 	Short: "Do a restore of the goto-paths file",
+	Long: `Do a restore of the goto-paths file from a backup.
+By default the goto-paths file is overwritten with the backup. With --merge the gpaths of the backup
+are added to the goto-paths file, and the gpaths with the same path or abbreviation are solved with
+the strategy:
+  keep-current: keep the gpath of the goto-paths file (default)
+  take-backup:  replace the gpath of the goto-paths file with the gpath of the backup
+  rename:       add the gpath of the backup with other abbreviation (if the path is different)
+  interactive:  ask how to solve each conflict`,
 	Example: `
-# Format: goto restore [ -t ] [ -i /path/file ]
+# Format: goto restore [ -t ] [ -i /path/file | --last-good | --generation n ] [ --merge [ --strategy strategy ] ] [ --dry-run ]
 
 # Do a restore of goto-paths from a backup in the config directory
 goto restore
 
 # If you want to specify the input path
 goto restore -i /the/path/file.json.backup
+
+# If the goto-paths file is corrupted, restore the last valid copy of it
+goto restore --last-good
+
+# Restore the newest automatic backup (see "goto backup list")
+goto restore --generation 0
+
+# Add the gpaths of a backup that a teammate sent without losing yours
+goto restore -i team.json --merge --strategy rename
+
+# Show the changes (added, removed and changed gpaths) without restoring
+goto restore -i team.json --merge --dry-run
 	`,
 	Args: cobra.ExactArgs(0),
 	Run:  runRestore,
@@ -27,18 +52,109 @@ goto restore -i /the/path/file.json.backup
 
 func runRestore(cmd *cobra.Command, _ []string) {
 
-	//Parse all flags  (if is not passed, have already a default value)
+	//Parse all flags  (if is not passed, use the default backup of the profile in use)
 	input, err := cmd.Flags().GetString("input")
 	cobra.CheckErr(err)
 
-	cobra.CheckErr(core.RestoreGPaths(input, utils.TemporalFlagPassed(cmd)))
+	if !utils.FlagPassed(cmd, "input") {
+		input = utils.GetDefaultBackupFilePath()
+	}
+
+	//If the last-good flag is passed, restore the last valid copy of the goto-paths file
+	if utils.FlagPassed(cmd, "last-good") {
+		input = utils.GetLastGoodFilePath(utils.TemporalFlagPassed(cmd))
+	}
+
+	//If the generation flag is passed, restore an automatic backup (see "goto backup list")
+	if utils.FlagPassed(cmd, "generation") {
+		generation, _ := cmd.Flags().GetInt("generation")
+		input, err = utils.GetGenerationFilePath(generation)
+		cobra.CheckErr(err)
+	}
+
+	strategy, _ := cmd.Flags().GetString("strategy")
+	if utils.FlagPassed(cmd, "strategy") && !utils.FlagPassed(cmd, "merge") {
+		cobra.CheckErr(fmt.Errorf("the strategy can only be used with --merge"))
+	}
+
+	opts := core.RestoreOptions{
+		Merge:    utils.FlagPassed(cmd, "merge"),
+		Strategy: strategy,
+		DryRun:   utils.FlagPassed(cmd, "dry-run"),
+	}
+
+	//The interactive strategy asks in the terminal (/dev/tty, the stdout is captured by the shell function),
+	//without terminal the restore fails
+	if strategy == core.MergeInteractive {
+		if tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0); err == nil {
+			defer tty.Close()
+			opts.Resolve = newMergePrompt(tty)
+		}
+	}
+
+	diff, err := core.RestoreGPathsWithOptions(input, opts, utils.TemporalFlagPassed(cmd))
+	cobra.CheckErr(err)
+
+	if opts.DryRun {
+		printDiff(diff)
+		return
+	}
 
 	fmt.Printf("Restore complete in %s\n", utils.GetFilePath(utils.TemporalFlagPassed(cmd)))
 }
 
+// printDiff prints the gpaths added (+), removed (-) and changed (~)
+func printDiff(diff gpath.Diff) {
+	if diff.Empty() {
+		fmt.Println("No changes")
+		return
+	}
+
+	for _, gp := range diff.Added {
+		fmt.Printf("+ %s\n", gp.String())
+	}
+	for _, gp := range diff.Removed {
+		fmt.Printf("- %s\n", gp.String())
+	}
+	for _, c := range diff.Changed {
+		fmt.Printf("~ %s => %s\n", c.Before.String(), c.After.String())
+	}
+}
+
+// newMergePrompt returns a function that asks in the terminal how to solve each conflict of the merge
+func newMergePrompt(tty io.ReadWriter) func(conflict core.MergeConflict) (string, error) {
+	reader := bufio.NewReader(tty)
+	return func(conflict core.MergeConflict) (string, error) {
+		for {
+			fmt.Fprintf(tty, "Conflict:\n  current: %s\n  backup:  %s\n", conflict.Current.String(), conflict.Backup.String())
+			fmt.Fprint(tty, "[k]eep current, [t]ake backup, [r]ename backup: ")
+
+			answer, err := reader.ReadString('\n')
+			if err != nil {
+				return "", fmt.Errorf("the merge was cancelled")
+			}
+
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "k", "keep":
+				return core.MergeKeepCurrent, nil
+			case "t", "take":
+				return core.MergeTakeBackup, nil
+			case "r", "rename":
+				return core.MergeRename, nil
+			}
+		}
+	}
+}
+
 func init() {
 	RootCmd.AddCommand(RestoreCmd)
 
 	//Flags
 	RestoreCmd.Flags().StringP("input", "i", utils.GetDefaultBackupFilePath(), "The ubication of the backup file")
+	RestoreCmd.Flags().Bool("last-good", false, "Restore the last valid copy of the goto-paths file (kept on every change)")
+	RestoreCmd.Flags().Int("generation", 0, "Restore the automatic backup n (0 is the newest, see \"goto backup list\")")
+	RestoreCmd.MarkFlagsMutuallyExclusive("input", "last-good", "generation")
+	RestoreCmd.Flags().Bool("merge", false, "Merge the backup with the goto-paths file instead of overwriting it")
+	RestoreCmd.Flags().String("strategy", core.MergeKeepCurrent, "How to solve the conflicts of the merge: "+strings.Join(core.MergeStrategies, ", "))
+	RestoreCmd.Flags().Bool("dry-run", false, "Show the added, removed and changed gpaths without restoring")
 }
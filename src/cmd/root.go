@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"goto/src/config"
 	"goto/src/core"
 	"goto/src/utils"
 	"os"
@@ -21,7 +22,11 @@ paths and abbreviations.
 `,
 
 	Example: `
-# Format: goto [ -t ] { abbreviation | path | index }
+# Format: goto [ -t ] [ -i ] { abbreviation | path | index | search terms... }
+
+# Open the interactive picker (type to filter, Up/Down to move, Enter to go, Esc to cancel)
+goto
+goto -i proj # The picker starts filtering by "proj"
 
 # Move to the destination directory
 # "h" is the abbreviation of /home/user
@@ -30,27 +35,126 @@ goto h
 # You also can use "0" (that is the default index of the /home/user)
 goto 0
 
+# Move to a sub-directory of a goto-path, the first segment is the abbreviation or index
+goto h/Documents/work
+goto h Documents work # The same that the previous one
+
 # Or also you can use goto like cd, use a complete/relative path:
 goto /home/user/.config/goto
 
-# For a temporal gpaths you have to use temporal flag(-t / --temporal)
+# The abbreviations are searched in the temporal gpaths, then in the project files and then in the
+# goto-paths file (the order can be changed with GOTO_RESOLVE_ORDER, e.g. "permanent,project,temporal").
+# If an abbreviation is in more than one of them, a warning is printed. The indexes are the ones of "goto list"
+goto home
+
+# To use only the temporal gpaths use the temporal flag (-t / --temporal)
 goto -t home
 
+# Use the goto-paths of other profile (also with the GOTO_PROFILE environment variable)
+goto --profile work api
+
 # If you have a directory named like a number or like abbreviation you should use -d / --only-directory flag
 goto -d 1 # This will move to the directory "1" and don't move to the first path in the gpaths file
 goto -d h # This will move to the directory "h" and don't move to the path with the abbreviation "h"
+
+# To get the resolved path in a machine-readable format use the output flag (json, ndjson, tsv
+# or a Go template), in that case goto exits with status 0
+goto -o json h
+goto -o '{{.Path}}' h
+
+# If the argument is not an abbreviation, an index or a directory, goto searches the goto-paths
+# by abbreviation, base name and path segments. If more than one goto-path matches equally,
+# the candidates are printed and goto exits with an error
+goto proj api # Matches "/home/user/projects/billing-api"
+
+# If no goto-path matches, goto moves to the most "frecent" (frequent and recent)
+# visited directory that matches the arguments
+goto billing
 `,
-	//If don't have args, return a error
-	Args: cobra.ExactArgs(1),
+	//If don't have args, the interactive picker is opened
+	Args: cobra.ArbitraryArgs,
+
+	//Complete the abbreviations, the indexes and the directories
+	ValidArgsFunction: completeRoot,
 
-	Run: runRoot,
+	PersistentPreRun: persistentPreRunRoot,
+	Run:              runRoot,
+}
+
+// persistentPreRunRoot selects the profile of goto-paths and checks the settings before running any command
+func persistentPreRunRoot(cmd *cobra.Command, _ []string) {
+	profile, _ := cmd.Flags().GetString(utils.FlagProfile)
+
+	//A missing profile is not an error for the profile commands (e.g. to create it or switch to other one)
+	if err := utils.UseProfile(profile); err != nil && !isSubcommandOf(cmd, ProfileCmd) {
+		cobra.CheckErr(err)
+	}
+	if warning := utils.ProfileWarning(); warning != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+
+	//An invalid config file or environment variable is an error, except for the config commands (to fix it)
+	if !isSubcommandOf(cmd, ConfigCmd) {
+		cobra.CheckErr(config.Check())
+	}
+}
+
+// isSubcommandOf checks if the command is the parent command or one of its subcommands
+func isSubcommandOf(cmd *cobra.Command, parent *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == parent {
+			return true
+		}
+	}
+	return false
 }
 
 func runRoot(cmd *cobra.Command, args []string) {
 
-	path, err := core.ResolvePath(args, cmd.Flags().Changed("only-directory"), utils.TemporalFlagPassed(cmd))
+	var path string
+	var err error
+
+	//If no argument or the interactive flag is passed, the path is selected with the picker
+	if len(args) == 0 || cmd.Flags().Changed("interactive") {
+		path, err = core.PickPath(strings.Join(args, " "))
+	} else {
+		var resolution core.Resolution
+		resolution, err = core.Resolve(args, cmd.Flags().Changed("only-directory"), utils.TemporalFlagPassed(cmd))
+		path = resolution.Path
+
+		//If the abbreviation is in more than one layer, warn it (in stderr, the stdout is the path)
+		if resolution.Collision != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", resolution.Collision)
+		}
+	}
 	cobra.CheckErr(err)
 
+	//Record the visit in the navigation history and in the directory stack (if the shell will move to
+	//the path), a failure here must not avoid the navigation
+	_ = core.RecordVisit(path)
+	if wd, err := os.Getwd(); err == nil && navigates(cmd) {
+		_ = core.PushStack(wd, path)
+	}
+
+	exitWithPath(cmd, path)
+}
+
+// navigates checks if the shell will move to the path printed by exitWithPath (the output,
+// quotes and spaces flags only print it)
+func navigates(cmd *cobra.Command) bool {
+	return utils.GetOutputFormat(cmd) == utils.OutputText && !cmd.Flags().Changed("quotes") && !cmd.Flags().Changed("spaces")
+}
+
+// exitWithPath prints the path to move and exits with the navigate_exit_code setting (2 by default, the
+// status that the shell function expects to move to the path). With the output flag, the path is printed
+// in that format (exits with status 0).
+func exitWithPath(cmd *cobra.Command, path string) {
+
+	//If the output flag is passed, print the path in that format (without the exit status 2)
+	if printStructured(cmd, []utils.OutputEntry{core.ResolvedEntry(path, utils.TemporalFlagPassed(cmd))}) {
+		os.Exit(0)
+	}
+
 	//If quote flag is passed
 	if cmd.Flags().Changed("quotes") {
 		fmt.Println("\"" + path + "\"")
@@ -66,9 +170,9 @@ func runRoot(cmd *cobra.Command, args []string) {
 	//If quote flag is not passed
 	fmt.Println(path)
 
-	//Return 2 because is easier for the alias.sh
+	//Return the navigate exit status (2 by default) because is easier for the shell scripts
 	//only need if [[ "$?" == "2"]]
-	os.Exit(2)
+	os.Exit(config.Get().ExitCode())
 }
 
 // StartExecution adds all child commands to the root command and sets flags appropriately.
@@ -80,9 +184,17 @@ func StartExecution() {
 	}
 }
 
+// addNavigationFlags adds the flags of the navigation to a command that runs runRoot
+func addNavigationFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolP("quotes", "q", false, "Return the path between quotes")
+	cmd.Flags().BoolP("spaces", "s", false, "Return the path with substituted spaces")
+	cmd.Flags().BoolP("only-directory", "d", false, "Only check if the argument passed is a directory")
+	cmd.Flags().BoolP("interactive", "i", false, "Select the path with the interactive picker (the args are the initial filter)")
+}
+
 func init() {
-	RootCmd.Flags().BoolP("quotes", "q", false, "Return the path between quotes")
-	RootCmd.Flags().BoolP("spaces", "s", false, "Return the path with substituted spaces")
-	RootCmd.Flags().BoolP("only-directory", "d", false, "Only check if the argument passed is a directory")
+	addNavigationFlags(RootCmd)
 	RootCmd.PersistentFlags().BoolP("temporal", "t", false, "Do the action in the temporal gpath file")
+	RootCmd.PersistentFlags().StringP(utils.FlagProfile, "P", "", "The profile of goto-paths to use (also with the "+utils.PROFILE_ENV_VAR+" environment variable)")
+	RootCmd.PersistentFlags().StringP(utils.FlagOutput, "o", utils.OutputText, "The output format: text, json, ndjson, tsv or a Go template (e.g. \"{{.Index}} {{.Path}}\")")
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"goto/src/core"
 	"goto/src/utils"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -14,10 +15,19 @@ var SearchCmd = &cobra.Command{
 	Aliases: []string{"search", "find-path", "find"},
 	Short:   "Search goto-paths in the goto-paths file",
 	Example: `
-# Format: goto search [ -t ] { -p path | -a abbreviation }
+# Format: goto search [ -t | -L ] { -p path | -a abbreviation | --tag tag... } [ --tag-mode and|or ]
 # To search a specific goto-path you can use the Path or the Abbreviation 
 goto search --path ~/Documents
 goto search --abbv docs
+
+# To search all the goto-paths with the tags "acme" and "api"
+goto search --tag acme --tag api
+
+# To search all the goto-paths with the tag "acme" or the tag "personal"
+goto search --tag acme,personal --tag-mode or
+
+# To search in all the layers (temporal, project and permanent), like goto resolves the abbreviations
+goto search --layers --abbv docs
 `,
 	PreRun: preRunSearch,
 	Run:    runSearch,
@@ -25,14 +35,14 @@ goto search --abbv docs
 
 func preRunSearch(cmd *cobra.Command, args []string) {
 
-	//If the number or flags are 0 or more than 2, return an error
-	if cmd.Flags().NFlag() == 0 || cmd.Flags().NFlag() > 2 {
+	//If the path and the abbreviation flags are passed, return an error
+	if utils.PathFlagPassed(cmd) && utils.AbbreviationFlagPassed(cmd) {
 		cobra.CheckErr("you must specify only one flag to find a gpath (Or Path or Abbreviation)")
 	}
 
-	//If only one flags is passed and it is the temporary flags, return an error
-	if cmd.Flags().NFlag() == 1 && utils.TemporalFlagPassed(cmd) {
-		cobra.CheckErr("you must specify one flag to find a gpath (Or Path or Abbreviation)")
+	//If none of the flags to identify the gpath is passed (e.g. only the temporary flag), return an error
+	if !utils.PathFlagPassed(cmd) && !utils.AbbreviationFlagPassed(cmd) && !utils.TagFlagPassed(cmd) {
+		cobra.CheckErr("you must specify one flag to find a gpath (Or Path or Abbreviation or Tag)")
 	}
 }
 
@@ -40,12 +50,90 @@ func runSearch(cmd *cobra.Command, _ []string) {
 	path, _ := cmd.Flags().GetString(utils.FlagPath)
 	abbv, _ := cmd.Flags().GetString(utils.FlagAbbreviation)
 
+	if utils.FlagPassed(cmd, "layers") {
+		runSearchLayers(cmd, path, abbv)
+		return
+	}
+
+	//If only the tags are passed, list all gpaths with that tags
+	if !utils.PathFlagPassed(cmd) && !utils.AbbreviationFlagPassed(cmd) {
+		tags, matchAll := utils.GetTagsFilter(cmd)
+
+		indexes, gpaths, err := core.SearchByTags(tags, matchAll, utils.TemporalFlagPassed(cmd))
+		cobra.CheckErr(err)
+
+		entries := make([]utils.OutputEntry, 0, len(gpaths))
+		for i := range gpaths {
+			entries = append(entries, utils.NewOutputEntry(indexes[i], gpaths[i]))
+		}
+		if printStructured(cmd, entries) {
+			return
+		}
+
+		for i := range gpaths {
+			fmt.Printf("%v - %s\n", indexes[i], gpaths[i].String())
+		}
+		return
+	}
+
 	idx, gpath, err := core.SearchPath(path, abbv, utils.TemporalFlagPassed(cmd))
 	cobra.CheckErr(err)
 
+	//If the tags are passed too, the gpath found must have them
+	if utils.TagFlagPassed(cmd) {
+		tags, matchAll := utils.GetTagsFilter(cmd)
+		if !gpath.HasTags(tags, matchAll) {
+			cobra.CheckErr(fmt.Errorf("the path \"%s\" doesn't have the tags \"%s\"", gpath.Path, strings.Join(tags, ", ")))
+		}
+	}
+
+	if printStructured(cmd, []utils.OutputEntry{utils.NewOutputEntry(idx, *gpath)}) {
+		return
+	}
+
 	fmt.Printf("%v - %s\n", idx, gpath.String())
 }
 
+// runSearchLayers searches the gpaths in all the layers, like the abbreviations are resolved, and shows the layer
+func runSearchLayers(cmd *cobra.Command, path, abbv string) {
+	if utils.TemporalFlagPassed(cmd) {
+		cobra.CheckErr(fmt.Errorf("the layers flag searches in all the layers, it can't be used with the temporal flag"))
+	}
+
+	var found []core.LayeredGPath
+	if !utils.PathFlagPassed(cmd) && !utils.AbbreviationFlagPassed(cmd) {
+		tags, matchAll := utils.GetTagsFilter(cmd)
+
+		layers, err := core.SearchLayersByTags(tags, matchAll)
+		cobra.CheckErr(err)
+		found = layers
+	} else {
+		layer, err := core.SearchLayers(path, abbv)
+		cobra.CheckErr(err)
+
+		//If the tags are passed too, the gpath found must have them
+		if utils.TagFlagPassed(cmd) {
+			tags, matchAll := utils.GetTagsFilter(cmd)
+			if !layer.HasTags(tags, matchAll) {
+				cobra.CheckErr(fmt.Errorf("the path \"%s\" doesn't have the tags \"%s\"", layer.Path, strings.Join(tags, ", ")))
+			}
+		}
+		found = []core.LayeredGPath{*layer}
+	}
+
+	entries := make([]utils.OutputEntry, 0, len(found))
+	for _, l := range found {
+		entries = append(entries, l.OutputEntry())
+	}
+	if printStructured(cmd, entries) {
+		return
+	}
+
+	for _, l := range found {
+		fmt.Printf("[%s] %v - %s\n", l.Layer, l.Index, l.String())
+	}
+}
+
 func init() {
 	//Add this command to RootCommand
 	RootCmd.AddCommand(SearchCmd)
@@ -53,4 +141,7 @@ func init() {
 	//Flags
 	SearchCmd.Flags().StringP(utils.FlagPath, "p", "", "The Path to delete")
 	SearchCmd.Flags().StringP(utils.FlagAbbreviation, "a", "", "The Abbreviation of the Path")
+	SearchCmd.Flags().BoolP("layers", "L", false, "Search in all the layers (temporal, project and permanent) like goto resolves the abbreviations, and show the layer")
+	registerGPathFlagsCompletion(SearchCmd)
+	utils.AddTagsFilterFlags(SearchCmd)
 }
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// StackCmd represents the stack command
+var StackCmd = &cobra.Command{
+	Use:   "stack [index]",
+	Short: "List the directory stack of the shell session or go to one of its directories",
+	Example: `
+# Format: goto stack [ index ]
+
+# List the stack, the current directory is marked with ">"
+goto stack
+
+# Go to the directory with the index 2 of the stack (exit with status 2)
+goto stack 2
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runStack,
+}
+
+func runStack(cmd *cobra.Command, args []string) {
+
+	//If an index is passed, go to that directory
+	if len(args) == 1 {
+		index, err := strconv.Atoi(args[0])
+		if err != nil {
+			cobra.CheckErr(fmt.Errorf("the index \"%s\" is not a number", args[0]))
+		}
+
+		path, err := core.JumpStack(index, navigates(cmd))
+		cobra.CheckErr(err)
+
+		exitWithPath(cmd, path)
+	}
+
+	stack, err := core.GetStack()
+	cobra.CheckErr(err)
+
+	//If the output flag is passed, print the stack in that format
+	entries := make([]utils.OutputEntry, 0, len(stack.Entries))
+	for i, dir := range stack.Entries {
+		entries = append(entries, utils.NewOutputEntry(i, gpath.GotoPath{Path: dir}))
+	}
+	if printStructured(cmd, entries) {
+		return
+	}
+
+	if len(stack.Entries) == 0 {
+		fmt.Println("The directory stack is empty")
+		return
+	}
+
+	for i, dir := range stack.Entries {
+		marker := " "
+		if i == stack.Position {
+			marker = ">"
+		}
+		fmt.Printf("%s %v - %s\n", marker, i, dir)
+	}
+}
+
+func init() {
+	RootCmd.AddCommand(StackCmd)
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"goto/src/core"
+	"goto/src/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// UndoCmd represents the undo command
+var UndoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the last change of the goto-paths file",
+	Long: `Undo the last change of the goto-paths file (add-path, delete-path, update-path, restore or import).
+It fails if the goto-paths file was changed after it without goto.
+A promote or a demote changes both files, undoing it from any of them reverts both.`,
+	Example: `
+# Format: goto undo [ -t ]
+
+# Undo the last change
+goto undo
+
+# Undo the last change of the temporal file
+goto undo -t
+`,
+	Args: cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, _ []string) {
+		operation, err := core.Undo(utils.TemporalFlagPassed(cmd))
+		cobra.CheckErr(err)
+
+		fmt.Printf("Undone: %s\n", operation)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(UndoCmd)
+}
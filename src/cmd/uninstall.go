@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"goto/src/core"
+
+	"github.com/spf13/cobra"
+)
+
+// UninstallCmd represents the uninstall command
+var UninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Reverse what init did: remove the goto block of the rc files, the shell scripts and goto.bin",
+	Long: `Reverse what init did: remove the block that loads goto from the rc files, the shell scripts (alias.sh,
+alias.zsh...), the completion scripts, goto.bin and the copies of the rc files made by init (.goto-backup).
+The goto-paths are kept unless --purge is passed. With --export, the goto-paths are exported first and,
+if the export fails, nothing is uninstalled.`,
+	Example: `
+# Show what would be removed
+goto uninstall --dry-run
+
+# Uninstall goto but keep the goto-paths (and the profiles, the backups...)
+goto uninstall
+
+# Export the goto-paths and remove everything
+goto uninstall --export ~/goto-paths.json --purge
+`,
+	Args: cobra.NoArgs,
+	Run:  runUninstall,
+}
+
+func runUninstall(cmd *cobra.Command, _ []string) {
+	purge, _ := cmd.Flags().GetBool("purge")
+	export, _ := cmd.Flags().GetString("export")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	actions, err := core.Uninstall(core.UninstallOptions{Purge: purge, Export: export, DryRun: dryRun})
+
+	//The done actions are printed also if there is an error
+	for _, action := range actions {
+		if dryRun {
+			fmt.Println("Would " + action.String())
+		} else {
+			fmt.Println("Done: " + action.String())
+		}
+
+		for _, line := range action.Lines {
+			fmt.Println("  - " + line)
+		}
+	}
+	cobra.CheckErr(err)
+
+	if len(actions) == 0 {
+		fmt.Println("Nothing to uninstall")
+		return
+	}
+
+	if !dryRun {
+		fmt.Println("Restart your terminal to unload the goto function")
+	}
+}
+
+func init() {
+	RootCmd.AddCommand(UninstallCmd)
+	UninstallCmd.Flags().Bool("purge", false, "Also remove the goto-paths, the profiles, the backups and the history")
+	UninstallCmd.Flags().String("export", "", "Export the goto-paths to the file before uninstalling")
+	UninstallCmd.Flags().Bool("dry-run", false, "Show what would be removed without changing anything")
+}
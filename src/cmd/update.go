@@ -14,7 +14,7 @@ var UpdateCmd = &cobra.Command{
 	Aliases: []string{"upd", "update", "modify-path", "mod"},
 	Short:   "Update a path from goto-path file",
 	Long: `
-To use the update-path command you have 9 modes to update, each mode needs two args, 
+To use the update-path command you have 15 modes to update, each mode needs two args, 
 the first to identify the goto-path and the second specific to what is to be updated. 
 
 Modes:
@@ -27,6 +27,12 @@ Modes:
 - A "Index" and a new "Path" (indx-path)
 - A "Index" and a new "Abbreviation" (indx-abbv)
 - A "Index" and a new "Index" (indx-indx)
+- A "Path" and new "Tags" separated by commas (path-tags)
+- A "Abbreviation" and new "Tags" separated by commas (abbv-tags)
+- A "Index" and new "Tags" separated by commas (indx-tags)
+- A "Path" and a new "Description" (path-desc)
+- A "Abbreviation" and a new "Description" (abbv-desc)
+- A "Index" and a new "Description" (indx-desc)
 `,
 
 	Example: `
@@ -46,10 +52,36 @@ goto update-path path-abbv --path /home/myuser --new home
 
 # Or if you want to update the abbreviation of the home
 goto update abbv-abbv --abbv h --new home
+
+# Replace the tags of the home (an empty value removes all the tags)
+goto update abbv-tags --abbv h --new personal,home
+
+# Update the description of the home
+goto update ad --abbv h --new "My home directory"
 `,
-	Args:   cobra.RangeArgs(0, 1),
-	PreRun: preRunUpdate,
-	Run:    runUpdate,
+	Args:              cobra.RangeArgs(0, 1),
+	ValidArgsFunction: completeUpdateModes,
+	PreRun:            preRunUpdate,
+	Run:               runUpdate,
+}
+
+// The modes of update-path (long and short form)
+var updateModes = [][]string{
+	{"path-path", "pp"}, // 0
+	{"path-abbv", "pa"}, // 1
+	{"path-indx", "pi"}, // 2
+	{"abbv-path", "ap"}, // 3
+	{"abbv-abbv", "aa"}, // 4
+	{"abbv-indx", "ai"}, // 5
+	{"indx-path", "ip"}, // 6
+	{"indx-abbv", "ia"}, // 7
+	{"indx-indx", "ii"}, // 8
+	{"path-tags", "pt"}, // 9
+	{"abbv-tags", "at"}, // 10
+	{"indx-tags", "it"}, // 11
+	{"path-desc", "pd"}, // 12
+	{"abbv-desc", "ad"}, // 13
+	{"indx-desc", "id"}, // 14
 }
 
 func preRunUpdate(cmd *cobra.Command, args []string) {
@@ -61,29 +93,17 @@ func preRunUpdate(cmd *cobra.Command, args []string) {
 
 	// If no value for new flags is passed, return a error
 	if !utils.FlagPassed(cmd, "new") {
-		cobra.CheckErr("must be specify the new filed to update (path/abbreviation/index)")
+		cobra.CheckErr("must be specify the new filed to update (path/abbreviation/index/tags/description)")
 	}
 
 }
 
 func runUpdate(cmd *cobra.Command, args []string) {
 
-	modes := [][]string{
-		{"path-path", "pp"}, // 0
-		{"path-abbv", "pa"}, // 1
-		{"path-indx", "pi"}, // 2
-		{"abbv-path", "ap"}, // 3
-		{"abbv-abbv", "aa"}, // 4
-		{"abbv-indx", "ai"}, // 5
-		{"indx-path", "ip"}, // 6
-		{"indx-abbv", "ia"}, // 7
-		{"indx-indx", "ii"}, // 8
-	}
-
 	//If modes is passed, show all modes
 	if utils.FlagPassed(cmd, "modes") {
-		for i := range modes {
-			fmt.Println("Long form:", modes[i][0], "|", "Short form:", modes[i][1])
+		for i := range updateModes {
+			fmt.Println("Long form:", updateModes[i][0], "|", "Short form:", updateModes[i][1])
 		}
 		return
 	}
@@ -108,9 +128,10 @@ func init() {
 	UpdateCmd.Flags().StringP(utils.FlagPath, "p", "", "The Path to delete")
 	UpdateCmd.Flags().StringP(utils.FlagAbbreviation, "a", "", "The Abbreviation of the Path")
 	UpdateCmd.Flags().IntP(utils.FlagIndex, "i", -1, "The Index of the Path")
+	registerGPathFlagsCompletion(UpdateCmd)
 
 	//Flags "Update To"
-	UpdateCmd.Flags().StringP("new", "n", "", "The Path or Abbreviation new")
+	UpdateCmd.Flags().StringP("new", "n", "", "The new Path, Abbreviation, Index, Tags or Description")
 
 	//Flag info
 	UpdateCmd.Flags().BoolP("modes", "m", false, "Print all modes formats")
@@ -3,29 +3,140 @@ package cmd
 import (
 	"fmt"
 	"goto/src/core"
+	"goto/src/gpath"
 	"goto/src/utils"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
+// Exit codes of valid-paths
+const (
+	exitProblemsFound = 3
+	exitProblemsFixed = 4
+)
+
 // ValidCmd represents the addGPath command
 var ValidCmd = &cobra.Command{
 	Use:     "valid-paths",
 	Aliases: []string{"valid", "check-paths", "check"},
 	Args:    cobra.ExactArgs(0),
 	Short:   "Validate all paths from goto-paths file",
+	Long: `Validate all paths from goto-paths file and report all the problems with their indexes:
+missing paths, paths that are not directories, permission denied, symlink loops, repeated paths
+or abbreviations and invalid abbreviations or tags.
+
+The problems can be fixed with --fix:
+  delete:        delete the gpaths whose path can't be used (missing, not a directory, no permission or repeated)
+  relocate:      search the missing paths by their directory name under the roots (--root, default the home directory)
+  home-relative: move the missing paths in the home directory of other user or machine to your home directory
+                 (stored as "~/...", so they keep working if the home changes again)
+With any mode, the repeated paths are deleted and the repeated abbreviations are renamed.
+
+Exit status: 0 if all paths are valid, 3 if there are problems and 4 if all the problems were fixed.`,
+	Example: `
+# Format: goto valid-paths [ -t ] [ -o format ] [ --fix mode [ --root dir ]... ]
+
+# Validate all paths
+goto valid-paths
+
+# Get the validation status of each path as JSON (exit with status 3 if any is invalid)
+goto valid-paths -o json
+
+# Delete the gpaths that don't exist anymore
+goto valid-paths --fix delete
+
+# Search the moved directories under ~/work and ~/personal
+goto valid-paths --fix relocate --root ~/work --root ~/personal
+`,
 
 	Run: runValid,
 }
 
 func runValid(cmd *cobra.Command, _ []string) {
+	useTemporal := utils.TemporalFlagPassed(cmd)
+
+	//If the fix flag is passed, fix the problems and report the fixes and the remaining problems
+	if utils.FlagPassed(cmd, "fix") {
+		mode, _ := cmd.Flags().GetString("fix")
+		roots, _ := cmd.Flags().GetStringSlice("root")
+		if len(roots) == 0 {
+			home, err := os.UserHomeDir()
+			cobra.CheckErr(err)
+			roots = []string{home}
+		}
+
+		fixes, remaining, err := core.FixPaths(mode, roots, useTemporal)
+		cobra.CheckErr(err)
 
-	cobra.CheckErr(core.ValidatePaths(utils.TemporalFlagPassed(cmd)))
+		for _, f := range fixes {
+			fmt.Printf("Fixed: %v - %s (%s)\n", f.Problem.Index, f.Problem.GotoPath.String(), f.Action)
+		}
+		printProblems(remaining)
 
-	fmt.Println("All paths are valid <3")
+		if len(remaining) > 0 {
+			os.Exit(exitProblemsFound)
+		}
+		if len(fixes) > 0 {
+			os.Exit(exitProblemsFixed)
+		}
+		fmt.Println("All paths are valid <3")
+		return
+	}
+
+	gpaths, problems, err := core.CheckPaths(useTemporal)
+	cobra.CheckErr(err)
+
+	//If the output flag is passed, print the status of each gpath in that format
+	if utils.GetOutputFormat(cmd) != utils.OutputText {
+		entries := make([]utils.OutputEntry, 0, len(gpaths))
+		for i := range gpaths {
+			entries = append(entries, utils.NewOutputEntry(i, gpaths[i]).WithStatus(problemsError(problems, i)))
+		}
+		printStructured(cmd, entries)
+	} else {
+		printProblems(problems)
+	}
+
+	if len(problems) > 0 {
+		os.Exit(exitProblemsFound)
+	}
+
+	if utils.GetOutputFormat(cmd) == utils.OutputText {
+		fmt.Println("All paths are valid <3")
+	}
+}
+
+// printProblems prints the problems with the index of the gpath
+func printProblems(problems []gpath.Problem) {
+	for _, p := range problems {
+		fmt.Printf("%v - %s: %s\n", p.Index, p.Kind, p.Message)
+	}
+	if len(problems) > 0 {
+		fmt.Printf("%v problems found\n", len(problems))
+	}
+}
+
+// problemsError returns the problems of the gpath in the index as an error (nil if it doesn't have problems)
+func problemsError(problems []gpath.Problem, index int) error {
+	messages := []string{}
+	for _, p := range problems {
+		if p.Index == index {
+			messages = append(messages, p.Message)
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
 }
 
 func init() {
 	//Add this command to RootCmd
 	RootCmd.AddCommand(ValidCmd)
+
+	//Flags
+	ValidCmd.Flags().String("fix", "", "Fix the problems: "+strings.Join(core.FixModes, ", "))
+	ValidCmd.Flags().StringSlice("root", nil, "A directory where the missing paths are searched with --fix relocate (can be repeated)")
 }
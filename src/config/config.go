@@ -0,0 +1,195 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/bytedance/sonic"
+)
+
+const (
+	// Name of the config file in the config directory of goto
+	CONFIG_FILE_NAME = "config.json"
+
+	// Prefix of the environment variables that override the settings (e.g. GOTO_NAVIGATE_MESSAGE)
+	ENV_PREFIX = "GOTO_"
+)
+
+// DefaultEntry is a gpath of a new goto-paths file
+type DefaultEntry struct {
+	Path         string `json:"path"`
+	Abbreviation string `json:"abbreviation"`
+}
+
+// Settings is the configuration of goto. The zero value of a setting means its default value.
+type Settings struct {
+	// The gpaths of a new goto-paths file ("~" and "$VAR" are expanded). If it is empty,
+	// the home ("h") and the directory of the file ("config") are added.
+	DefaultEntries []DefaultEntry `json:"default_entries,omitempty"`
+
+	// The default file of backup and restore, relative to the config directory ("~" and "$VAR" are expanded,
+	// "{profile}" is replaced by the profile in use). If it is empty, the goto-paths file with the ".backup"
+	// extension is used.
+	BackupFile string `json:"backup_file,omitempty"`
+
+	// The number of automatic backups kept by profile (0 disables them). If it is nil,
+	// DefaultBackupGenerations are kept.
+	BackupGenerations *int `json:"backup_generations,omitempty"`
+
+	// The exit status of goto when the shell must move to the printed path
+	NavigateExitCode int `json:"navigate_exit_code,omitempty"`
+
+	// The message printed by the shell scripts before the path after moving ("none" to don't print anything)
+	NavigateMessage string `json:"navigate_message,omitempty"`
+
+	// A regular expression that the abbreviations must match and their maximum length (0 without limit),
+	// besides the rules that always apply (not empty, without spaces and not a number)
+	AbbreviationPattern   string `json:"abbreviation_pattern,omitempty"`
+	AbbreviationMaxLength int    `json:"abbreviation_max_length,omitempty"`
+
+	// The order of the layers used to resolve the abbreviations (e.g. "temporal,project,permanent")
+	ResolveOrder string `json:"resolve_order,omitempty"`
+}
+
+var (
+	// The config file, the settings loaded from it (without the environment variables)
+	// and the error loading it
+	configFile   string
+	fileSettings Settings
+	loadErr      error
+	mutex        sync.RWMutex
+)
+
+// Setup loads the config file of the config directory. If the file doesn't exist, the default settings are used.
+// If it can't be loaded, the default settings are used and the error is returned (and kept, see Check).
+func Setup(configDir string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	configFile = filepath.Join(configDir, CONFIG_FILE_NAME)
+	fileSettings = Settings{}
+	loadErr = nil
+
+	settings, err := LoadFile(configFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		loadErr = err
+		return err
+	}
+
+	fileSettings = settings
+	return nil
+}
+
+// Check returns the error loading the config file or, if it was loaded, the error of the first
+// environment variable with an invalid value (they are ignored by Get)
+func Check() error {
+	mutex.RLock()
+	err := loadErr
+	mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if value, ok := os.LookupEnv(key.EnvVar()); ok && value != "" {
+			if err := key.set(&Settings{}, value); err != nil {
+				return fmt.Errorf("invalid value of %s: %v", key.EnvVar(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// GetFilePath returns the path of the config file
+func GetFilePath() string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return configFile
+}
+
+// Get returns the settings in use: the ones of the config file overridden by the environment variables
+// (an invalid value of an environment variable is ignored)
+func Get() Settings {
+	mutex.RLock()
+	settings := fileSettings
+	mutex.RUnlock()
+
+	for _, key := range keys {
+		if value, ok := os.LookupEnv(key.EnvVar()); ok && value != "" {
+			_ = key.set(&settings, value)
+		}
+	}
+	return settings
+}
+
+// GetFileSettings returns the settings of the config file (without the environment variables)
+func GetFileSettings() Settings {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return fileSettings
+}
+
+// Update validates the settings and overwrites the config file with them
+func Update(settings Settings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if err := SaveFile(settings, configFile); err != nil {
+		return err
+	}
+	fileSettings = settings
+	loadErr = nil
+	return nil
+}
+
+// LoadFile loads and validates the settings of a config file
+func LoadFile(file string) (Settings, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return Settings{}, err
+	}
+	defer f.Close()
+
+	var settings Settings
+	if err := sonic.ConfigFastest.NewDecoder(bufio.NewReader(f)).Decode(&settings); err != nil {
+		return Settings{}, fmt.Errorf("error parsing the config file \"%s\": %v", file, err)
+	}
+
+	if err := settings.Validate(); err != nil {
+		return Settings{}, fmt.Errorf("invalid config file \"%s\": %v", file, err)
+	}
+	return settings, nil
+}
+
+// SaveFile writes the settings in the config file
+func SaveFile(settings Settings, file string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	data, err := sonic.ConfigDefault.MarshalIndent(settings, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0644)
+}
+
+// Validate checks the value of all the settings
+func (s Settings) Validate() error {
+	for _, key := range keys {
+		if err := key.set(&Settings{}, key.get(s)); err != nil {
+			return fmt.Errorf("%s: %v", key.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+const (
+	// The default exit status of goto when the shell must move to the path
+	DefaultNavigateExitCode = 2
+
+	// The default message printed by the shell scripts after moving
+	DefaultNavigateMessage = "Go to: "
+
+	// The default number of automatic backups kept by profile
+	DefaultBackupGenerations = 10
+
+	// The value of NavigateMessage to don't print any message
+	NoNavigateMessage = "none"
+)
+
+// The exit statuses that goto uses for other things (errors and the problems of valid-paths)
+var reservedExitCodes = []int{0, 1, 3, 4}
+
+// The characters that can't be in the navigate message, because it is written inside the shell scripts
+const forbiddenMessageChars = "\"'`$\\!%\n\r"
+
+// Key is a setting that can be read and changed by its name (e.g. with "goto config set")
+type Key struct {
+	Name        string
+	Description string
+
+	// The default value (as text)
+	Default string
+
+	// get returns the value of the setting as text ("" if it has its default value) and set changes it
+	// from a text ("" to set its default value), set fails if the value is invalid
+	get func(s Settings) string
+	set func(s *Settings, value string) error
+}
+
+// EnvVar returns the environment variable that overrides the setting (e.g. GOTO_NAVIGATE_MESSAGE)
+func (k Key) EnvVar() string {
+	return ENV_PREFIX + strings.ToUpper(k.Name)
+}
+
+// The settings by name (in the order of the Settings fields)
+var keys = []*Key{
+	{
+		Name:        "default_entries",
+		Description: "The gpaths of a new goto-paths file, a JSON array of {\"path\", \"abbreviation\"} (\"~\" and \"$VAR\" are expanded)",
+		Default:     `[{"path":"~","abbreviation":"h"},{"path":"<directory of the file>","abbreviation":"config"}]`,
+		get: func(s Settings) string {
+			if len(s.DefaultEntries) == 0 {
+				return ""
+			}
+			data, _ := sonic.ConfigDefault.Marshal(s.DefaultEntries)
+			return string(data)
+		},
+		set: func(s *Settings, value string) error {
+			entries := []DefaultEntry{}
+			if value != "" {
+				if err := sonic.ConfigFastest.UnmarshalFromString(value, &entries); err != nil {
+					return fmt.Errorf("it must be a JSON array of {\"path\", \"abbreviation\"}: %v", err)
+				}
+			}
+			for _, e := range entries {
+				if strings.TrimSpace(e.Path) == "" || strings.TrimSpace(e.Abbreviation) == "" {
+					return fmt.Errorf("the path and the abbreviation of the entries can't be empty")
+				}
+			}
+			s.DefaultEntries = entries
+			return nil
+		},
+	},
+	{
+		Name:        "backup_file",
+		Description: "The default file of backup and restore, relative to the config directory (\"~\" and \"$VAR\" are expanded, \"{profile}\" is the profile in use)",
+		Default:     "<goto-paths file>.backup",
+		get:         func(s Settings) string { return s.BackupFile },
+		set: func(s *Settings, value string) error {
+			s.BackupFile = strings.TrimSpace(value)
+			return nil
+		},
+	},
+	{
+		Name:        "backup_generations",
+		Description: "The number of automatic backups kept by profile, saved before each change of the goto-paths file (0 disables them)",
+		Default:     strconv.Itoa(DefaultBackupGenerations),
+		get: func(s Settings) string {
+			if s.BackupGenerations == nil {
+				return ""
+			}
+			return strconv.Itoa(*s.BackupGenerations)
+		},
+		set: func(s *Settings, value string) error {
+			if strings.TrimSpace(value) == "" {
+				s.BackupGenerations = nil
+				return nil
+			}
+			n, err := parseInt(value)
+			if err != nil {
+				return err
+			}
+			if n < 0 {
+				return fmt.Errorf("the number of backups can't be negative")
+			}
+			s.BackupGenerations = &n
+			return nil
+		},
+	},
+	{
+		Name:        "navigate_exit_code",
+		Description: "The exit status of goto when the shell must move to the path (run \"goto init\" after changing it)",
+		Default:     strconv.Itoa(DefaultNavigateExitCode),
+		get:         func(s Settings) string { return intValue(s.NavigateExitCode) },
+		set: func(s *Settings, value string) error {
+			code, err := parseInt(value)
+			if err != nil {
+				return err
+			}
+			if code != 0 && (code < 2 || code > 125 || slices.Contains(reservedExitCodes, code)) {
+				return fmt.Errorf("the exit status must be between 2 and 125 and it can't be 3 or 4 (used by valid-paths)")
+			}
+			s.NavigateExitCode = code
+			return nil
+		},
+	},
+	{
+		Name:        "navigate_message",
+		Description: "The message printed before the path after moving, \"" + NoNavigateMessage + "\" to don't print it (run \"goto init\" after changing it)",
+		Default:     DefaultNavigateMessage,
+		get:         func(s Settings) string { return s.NavigateMessage },
+		set: func(s *Settings, value string) error {
+			if strings.ContainsAny(value, forbiddenMessageChars) {
+				return fmt.Errorf("the message can't contain quotes, \"$\", \"\\\", \"!\", \"%%\" or new lines")
+			}
+			s.NavigateMessage = value
+			return nil
+		},
+	},
+	{
+		Name:        "abbreviation_pattern",
+		Description: "A regular expression that the new abbreviations must match (e.g. \"^[a-z][a-z0-9-]*$\")",
+		Default:     "",
+		get:         func(s Settings) string { return s.AbbreviationPattern },
+		set: func(s *Settings, value string) error {
+			if _, err := regexp.Compile(value); err != nil {
+				return fmt.Errorf("invalid regular expression: %v", err)
+			}
+			s.AbbreviationPattern = value
+			return nil
+		},
+	},
+	{
+		Name:        "abbreviation_max_length",
+		Description: "The maximum length of the new abbreviations (0 without limit)",
+		Default:     "0",
+		get:         func(s Settings) string { return intValue(s.AbbreviationMaxLength) },
+		set: func(s *Settings, value string) error {
+			length, err := parseInt(value)
+			if err != nil {
+				return err
+			}
+			if length < 0 {
+				return fmt.Errorf("the length can't be negative")
+			}
+			s.AbbreviationMaxLength = length
+			return nil
+		},
+	},
+	{
+		Name:        "resolve_order",
+		Description: "The order of the layers used to resolve the abbreviations without the temporal flag (a layer that is not in the list is not used)",
+		Default:     "temporal,project,permanent",
+		get:         func(s Settings) string { return s.ResolveOrder },
+		set: func(s *Settings, value string) error {
+			s.ResolveOrder = strings.TrimSpace(value)
+			return nil
+		},
+	},
+}
+
+// Keys returns the settings that can be read and changed by name
+func Keys() []Key {
+	list := make([]Key, 0, len(keys))
+	for _, k := range keys {
+		list = append(list, *k)
+	}
+	return list
+}
+
+// GetKey returns the setting with the name
+func GetKey(name string) (Key, error) {
+	for _, k := range keys {
+		if k.Name == name {
+			return *k, nil
+		}
+	}
+
+	names := make([]string, 0, len(keys))
+	for _, k := range keys {
+		names = append(names, k.Name)
+	}
+	return Key{}, fmt.Errorf("unknown setting \"%s\" (valid settings: %s)", name, strings.Join(names, ", "))
+}
+
+// RegisterValidator adds an extra validation to the values of a setting (e.g. to check the names of the
+// layers of resolve_order in the package that defines them). It panics if the setting doesn't exist.
+func RegisterValidator(name string, validate func(value string) error) {
+	for _, k := range keys {
+		if k.Name == name {
+			set := k.set
+			k.set = func(s *Settings, value string) error {
+				if value != "" {
+					if err := validate(value); err != nil {
+						return err
+					}
+				}
+				return set(s, value)
+			}
+			return
+		}
+	}
+	panic(fmt.Sprintf("unknown setting \"%s\"", name))
+}
+
+// Value returns the value of the setting in the settings ("" if it has its default value)
+func (k Key) Value(s Settings) string {
+	return k.get(s)
+}
+
+// Set changes the value of the setting in the settings ("" to set its default value)
+func (k Key) Set(s *Settings, value string) error {
+	return k.set(s, value)
+}
+
+// intValue returns the text of an integer setting ("" for 0, the default value)
+func intValue(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// parseInt parses the value of an integer setting ("" is 0, the default value)
+func parseInt(value string) (int, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("the value \"%s\" must be a number", value)
+	}
+	return n, nil
+}
+
+// ExitCode returns the exit status of goto when the shell must move to the path
+func (s Settings) ExitCode() int {
+	if s.NavigateExitCode == 0 {
+		return DefaultNavigateExitCode
+	}
+	return s.NavigateExitCode
+}
+
+// Generations returns the number of automatic backups kept by profile (0 if they are disabled)
+func (s Settings) Generations() int {
+	if s.BackupGenerations == nil {
+		return DefaultBackupGenerations
+	}
+	return *s.BackupGenerations
+}
+
+// Message returns the message printed by the shell scripts after moving (empty to don't print it)
+func (s Settings) Message() string {
+	switch s.NavigateMessage {
+	case "":
+		return DefaultNavigateMessage
+	case NoNavigateMessage:
+		return ""
+	default:
+		return s.NavigateMessage
+	}
+}
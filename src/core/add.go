@@ -1,36 +1,153 @@
 package core
 
 import (
+	"fmt"
 	"goto/src/gpath"
 	"goto/src/utils"
+	"slices"
+	"strings"
+	"time"
 )
 
+// AddOptions are the optional fields of a new goto-path.
+type AddOptions struct {
+	Tags        []string
+	Description string
+
+	// Add the path to the nearest project file (.goto.json) instead of the goto-paths file
+	Local bool
+
+	// Store the path relative to the home ("~/...") or to one of the PortableVars ("$VAR/..."),
+	// so the goto-paths file can be used in other machines
+	Portable     bool
+	PortableVars []string
+
+	// Only for the temporal gpaths: remove the gpath after this time (0 to keep it until reboot)
+	// and only show it in the current shell session
+	TTL     time.Duration
+	Session bool
+}
+
 // AddPath adds a new path to the goto-paths file.
 // It validates the input arguments before adding.
 func AddPath(pathArg, abbvArg string, useTemporal bool) error {
+	return AddPathWithOptions(pathArg, abbvArg, AddOptions{}, useTemporal)
+}
+
+// AddPathWithOptions adds a new path with the optional fields (tags, description) to the goto-paths file.
+// It validates the input arguments before adding.
+func AddPathWithOptions(pathArg, abbvArg string, opts AddOptions, useTemporal bool) error {
+	if opts.Local && useTemporal {
+		return fmt.Errorf("a path can't be added to the temporal file and to a project file at the same time")
+	}
+
+	if opts.Local {
+		return addLocalPath(pathArg, abbvArg, opts)
+	}
+
+	if (opts.TTL != 0 || opts.Session) && !useTemporal {
+		return fmt.Errorf("only a temporal path can expire or be scoped to a session")
+	}
+
+	unlock, err := utils.LockGPaths(useTemporal)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	gpaths, err := utils.LoadGPaths(useTemporal)
 	if err != nil {
 		return err
 	}
 
-	path, err := gpath.ValidPath(pathArg)
+	path, err := storedPath(pathArg, opts)
+	if err != nil {
+		return err
+	}
+
+	abbv, err := gpath.ValidNewAbbreviation(abbvArg)
 	if err != nil {
 		return err
 	}
 
-	abbv, err := gpath.ValidAbbreviation(abbvArg)
+	tags, err := gpath.ValidTags(opts.Tags)
 	if err != nil {
 		return err
 	}
 
-	gpaths = append(gpaths, gpath.GotoPath{
+	gp := gpath.GotoPath{
 		Path:         path,
 		Abbreviation: abbv,
-	})
+		Tags:         tags,
+		Description:  strings.TrimSpace(opts.Description),
+	}
+
+	if err := setTemporalLifetime(&gp, opts.TTL, opts.Session); err != nil {
+		return err
+	}
 
 	// Check for duplicates is handled by UpdateGPaths -> SaveGPathsFile -> CheckRepeatedItems
 	// But CheckRepeatedItems requires the array.
 	// Logic is consistent.
 
-	return utils.UpdateGPaths(useTemporal, gpaths)
+	return saveChange("add-path "+gp.String(), gpaths, append(slices.Clone(gpaths), gp), useTemporal)
+}
+
+// addLocalPath validates the input arguments and adds the new path to the nearest project file.
+func addLocalPath(pathArg, abbvArg string, opts AddOptions) error {
+	path, err := storedPath(pathArg, opts)
+	if err != nil {
+		return err
+	}
+
+	abbv, err := gpath.ValidNewAbbreviation(abbvArg)
+	if err != nil {
+		return err
+	}
+
+	tags, err := gpath.ValidTags(opts.Tags)
+	if err != nil {
+		return err
+	}
+
+	return addProjectPath(gpath.GotoPath{
+		Path:         path,
+		Abbreviation: abbv,
+		Tags:         tags,
+		Description:  strings.TrimSpace(opts.Description),
+	})
+}
+
+// storedPath validates the path to store in a new gpath. A portable path ("~/..." or "$VAR/...")
+// is kept, other paths are made absolute and, with the Portable option, portable if possible.
+func storedPath(pathArg string, opts AddOptions) (string, error) {
+	path, err := gpath.ValidStoredPath(pathArg)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.Portable && !gpath.IsPortable(path) {
+		path = gpath.PortablePath(path, opts.PortableVars)
+	}
+	return path, nil
+}
+
+// setTemporalLifetime sets when the temporal gpath expires (after the ttl, if it isn't 0)
+// and, if onlySession, the shell session that can see it
+func setTemporalLifetime(gp *gpath.GotoPath, ttl time.Duration, onlySession bool) error {
+	if ttl < 0 {
+		return fmt.Errorf("the time to live \"%v\" can't be negative", ttl)
+	}
+	if ttl > 0 {
+		gp.ExpiresAt = time.Now().Add(ttl).Unix()
+	}
+
+	if onlySession {
+		session, err := utils.GetSession()
+		if err != nil {
+			return err
+		}
+		gp.Session = session
+	}
+	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"goto/src/gpath"
 	"goto/src/utils"
 	"os"
+	"path/filepath"
 )
 
 // BackupGPaths backs up the current goto paths to outputPath.
@@ -18,5 +19,24 @@ func BackupGPaths(outputPath string, useTemporal bool) error {
 		return fmt.Errorf("the file \"%s\" already exists", outputPath)
 	}
 
+	// The directory can be missing if the backup_file setting is in other directory
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return err
+	}
+
 	return gpath.SaveGPathsFile(gpaths, outputPath)
 }
+
+// ListBackupGenerations returns the automatic backups of the goto-paths file, the newest first.
+func ListBackupGenerations() ([]gpath.Generation, error) {
+	return utils.GetGenerations()
+}
+
+// LoadBackupGeneration returns the gpaths of the automatic backup n (0 is the newest).
+func LoadBackupGeneration(n int) ([]gpath.GotoPath, error) {
+	file, err := utils.GetGenerationFilePath(n)
+	if err != nil {
+		return nil, err
+	}
+	return loadBackupFile(file)
+}
@@ -3,12 +3,19 @@ package core
 import (
 	"goto/src/gpath"
 	"goto/src/utils"
+	"slices"
 	"strconv"
 )
 
 // DeletePath deletes a path identified by path, abbreviation or index.
 // Returns the deleted path info or error.
 func DeletePath(pathArg, abbvArg string, indexArg int, useTemporal bool) (*gpath.GotoPath, error) {
+	unlock, err := utils.LockGPaths(useTemporal)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	gpaths, err := utils.LoadGPaths(useTemporal)
 	if err != nil {
 		return nil, err
@@ -33,14 +40,14 @@ func DeletePath(pathArg, abbvArg string, indexArg int, useTemporal bool) (*gpath
 	}
 
 	// Remove the element at targetIndex
-	gpaths = append(gpaths[:targetIndex], gpaths[targetIndex+1:]...)
+	after := slices.Delete(slices.Clone(gpaths), targetIndex, targetIndex+1)
 
 	// Validate again before saving
-	if err := gpath.CheckRepeatedItems(gpaths); err != nil {
+	if err := gpath.CheckRepeatedItems(after); err != nil {
 		return nil, err
 	}
 
-	if err := utils.UpdateGPaths(useTemporal, gpaths); err != nil {
+	if err := saveChange("delete-path "+deleted.String(), gpaths, after, useTemporal); err != nil {
 		return nil, err
 	}
 
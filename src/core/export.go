@@ -0,0 +1,198 @@
+package core
+
+import (
+	"fmt"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Characters that can't be used in the name of a shell variable
+var invalidVarCharsRegexp = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Exporter renders the gpaths in the bookmark format of other tool
+type Exporter struct {
+	Name        string
+	Description string
+
+	// Render the gpaths (sorted by abbreviation)
+	Render func(w io.Writer, gpaths []gpath.GotoPath) error
+}
+
+// The registered exporters by name
+var exporters = map[string]Exporter{}
+
+// RegisterExporter adds an exporter, an exporter with the same name is replaced
+func RegisterExporter(exporter Exporter) {
+	exporters[exporter.Name] = exporter
+}
+
+// GetExporter returns the exporter with the name
+func GetExporter(name string) (Exporter, error) {
+	exporter, ok := exporters[name]
+	if !ok {
+		return Exporter{}, fmt.Errorf("unknown export format \"%s\" (valid formats: %s)", name, strings.Join(ExporterNames(), ", "))
+	}
+	return exporter, nil
+}
+
+// ExporterNames returns the names of the registered exporters (sorted)
+func ExporterNames() []string {
+	names := make([]string, 0, len(exporters))
+	for name := range exporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExportGPaths writes the gpaths of the goto-paths file in the format of the exporter.
+// The gpaths are sorted by abbreviation so the output is always the same for the same gpaths.
+func ExportGPaths(w io.Writer, format string, useTemporal bool) error {
+	exporter, err := GetExporter(format)
+	if err != nil {
+		return err
+	}
+
+	gpaths, err := utils.LoadGPaths(useTemporal)
+	if err != nil {
+		return err
+	}
+
+	sorted := make([]gpath.GotoPath, len(gpaths))
+	copy(sorted, gpaths)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Abbreviation < sorted[j].Abbreviation
+	})
+
+	return exporter.Render(w, sorted)
+}
+
+// ExportGPathsToFile writes the export of the gpaths in the file (overwriting it)
+func ExportGPathsToFile(file, format string, useTemporal bool) error {
+	var sb strings.Builder
+	if err := ExportGPaths(&sb, format, useTemporal); err != nil {
+		return err
+	}
+	return os.WriteFile(file, []byte(sb.String()), 0644)
+}
+
+// The exporters of goto
+func init() {
+	RegisterExporter(Exporter{
+		Name:        "cdpath",
+		Description: "A CDPATH variable with all the paths (\"cd name\" works for the subdirectories of the paths)",
+		Render: func(w io.Writer, gpaths []gpath.GotoPath) error {
+			paths := []string{"."}
+			for _, gp := range gpaths {
+				paths = append(paths, gp.ExpandedPath())
+			}
+			_, err := fmt.Fprintf(w, "export CDPATH=%s\n", shellQuote(strings.Join(paths, ":")))
+			return err
+		},
+	})
+
+	RegisterExporter(Exporter{
+		Name:        "vars",
+		Description: "A shell variable by path, named as the abbreviation (cd \"$docs\")",
+		Render: renderVarLines(func(name string, gp gpath.GotoPath) string {
+			return fmt.Sprintf("%s=%s", name, shellQuote(gp.ExpandedPath()))
+		}),
+	})
+
+	RegisterExporter(Exporter{
+		Name:        "zsh",
+		Description: "The named directories of zsh, with \"hash -d\" (cd ~docs)",
+		Render: renderVarLines(func(name string, gp gpath.GotoPath) string {
+			return fmt.Sprintf("hash -d %s=%s", name, shellQuote(gp.ExpandedPath()))
+		}),
+	})
+
+	RegisterExporter(Exporter{
+		Name:        "fish",
+		Description: "A fish abbreviation by path that expands to \"cd path\"",
+		Render: renderLines(func(gp gpath.GotoPath) string {
+			return fmt.Sprintf("abbr -a -- %s %s", fishQuote(gp.Abbreviation), fishQuote("cd "+shellQuote(gp.ExpandedPath())))
+		}),
+	})
+
+	RegisterExporter(Exporter{
+		Name:        "bashmarks",
+		Description: "The .sdirs file of bashmarks",
+		Render: renderVarLines(func(name string, gp gpath.GotoPath) string {
+			return fmt.Sprintf("export DIR_%s=%s", name, shellQuote(gp.ExpandedPath()))
+		}),
+	})
+
+	RegisterExporter(Exporter{
+		Name:        "zoxide",
+		Description: "A z data file (path|rank|time), to import with \"zoxide import --from z\" (the time of the last visit with goto, or 0)",
+		Render: func(w io.Writer, gpaths []gpath.GotoPath) error {
+			// The time of the last visit of the navigation history, 0 for the paths never visited, so the
+			// output only changes with the visits (a history that can't be loaded is ignored, it is only a hint)
+			history, _ := utils.LoadHistory()
+			visits := map[string]int64{}
+			for _, h := range history {
+				visits[h.Path] = h.LastVisit
+			}
+
+			return renderLines(func(gp gpath.GotoPath) string {
+				return fmt.Sprintf("%s|1|%d", gp.ExpandedPath(), visits[gp.ExpandedPath()])
+			})(w, gpaths)
+		},
+	})
+}
+
+// renderLines returns a Render function that writes a line by gpath
+func renderLines(line func(gp gpath.GotoPath) string) func(w io.Writer, gpaths []gpath.GotoPath) error {
+	return func(w io.Writer, gpaths []gpath.GotoPath) error {
+		for _, gp := range gpaths {
+			if _, err := fmt.Fprintln(w, line(gp)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// renderVarLines returns a Render function that writes a line by gpath with the variable name of its
+// abbreviation (see varName). It fails if two abbreviations have the same variable name (e.g. "a-b" and "a_b").
+func renderVarLines(line func(name string, gp gpath.GotoPath) string) func(w io.Writer, gpaths []gpath.GotoPath) error {
+	return func(w io.Writer, gpaths []gpath.GotoPath) error {
+		abbvs := map[string]string{}
+		for _, gp := range gpaths {
+			name := varName(gp.Abbreviation)
+			if other, exists := abbvs[name]; exists {
+				return fmt.Errorf("the abbreviations \"%s\" and \"%s\" have the same variable name \"%s\", rename one of them", other, gp.Abbreviation, name)
+			}
+			abbvs[name] = gp.Abbreviation
+		}
+
+		return renderLines(func(gp gpath.GotoPath) string {
+			return line(varName(gp.Abbreviation), gp)
+		})(w, gpaths)
+	}
+}
+
+// varName converts the abbreviation in a valid name of a shell variable
+func varName(abbv string) string {
+	name := invalidVarCharsRegexp.ReplaceAllString(abbv, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// shellQuote quotes the value for a POSIX shell
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes the value for fish
+func fishQuote(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+}
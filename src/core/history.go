@@ -0,0 +1,27 @@
+package core
+
+import (
+	"goto/src/gpath"
+	"goto/src/utils"
+	"time"
+)
+
+// RecordVisit records a visit of the path in the navigation history.
+func RecordVisit(path string) error {
+	history, err := utils.LoadHistory()
+	if err != nil {
+		return err
+	}
+
+	return utils.UpdateHistory(gpath.RecordVisit(history, path, time.Now()))
+}
+
+// frecencyMatch returns the path of the history with the best frecency score that matches the keywords.
+func frecencyMatch(keywords []string) (string, bool) {
+	history, err := utils.LoadHistory()
+	if err != nil {
+		return "", false
+	}
+
+	return gpath.BestFrecencyMatch(history, keywords, time.Now())
+}
@@ -0,0 +1,238 @@
+package core
+
+import (
+	"fmt"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ImportedPath is a path read by an importer, the abbreviation and the score are optional
+type ImportedPath struct {
+	Path         string
+	Abbreviation string
+
+	// Paths with a higher score get the generated abbreviations first
+	Score float64
+}
+
+// Importer reads the bookmarks of another tool
+type Importer struct {
+	Name        string
+	Description string
+
+	// The file read when no file is passed ("-" is the stdin)
+	DefaultFile func() string
+
+	// Parse the content of the file
+	Parse func(r io.Reader) ([]ImportedPath, error)
+}
+
+// ImportIssue is a path of the import that was skipped or changed
+type ImportIssue struct {
+	Path   string
+	Reason string
+}
+
+// ImportPlan is the result of an import before writing it
+type ImportPlan struct {
+	// The gpaths that will be added
+	Added []gpath.GotoPath
+
+	// The paths that conflict with the existing gpaths (skipped or renamed)
+	Conflicts []ImportIssue
+
+	// The paths that are not valid (e.g. they don't exist anymore)
+	Skipped []ImportIssue
+}
+
+// The registered importers by name
+var importers = map[string]Importer{}
+
+// RegisterImporter adds an importer, an importer with the same name is replaced
+func RegisterImporter(importer Importer) {
+	importers[importer.Name] = importer
+}
+
+// GetImporter returns the importer with the name
+func GetImporter(name string) (Importer, error) {
+	importer, ok := importers[name]
+	if !ok {
+		return Importer{}, fmt.Errorf("unknown importer \"%s\" (valid importers: %s)", name, strings.Join(ImporterNames(), ", "))
+	}
+	return importer, nil
+}
+
+// ImporterNames returns the names of the registered importers (sorted)
+func ImporterNames() []string {
+	names := make([]string, 0, len(importers))
+	for name := range importers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PlanImport reads the file with the importer and returns the gpaths that would be added to the
+// goto-paths file, the conflicts with the existing gpaths and the invalid paths. Nothing is written.
+// If the file is empty, the default file of the importer is used ("-" is the stdin).
+func PlanImport(importerName, file string, useTemporal bool) (ImportPlan, error) {
+	importer, err := GetImporter(importerName)
+	if err != nil {
+		return ImportPlan{}, err
+	}
+
+	if file == "" {
+		file = importer.DefaultFile()
+	}
+
+	var reader io.Reader = os.Stdin
+	if file == "-" && isTerminal(os.Stdin) {
+		return ImportPlan{}, fmt.Errorf("the %s importer reads the stdin, pipe the data to goto or pass a file with -f", importer.Name)
+	}
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return ImportPlan{}, fmt.Errorf("can't open the file to import: %v", err)
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	imported, err := importer.Parse(reader)
+	if err != nil {
+		return ImportPlan{}, fmt.Errorf("can't parse the %s file \"%s\": %v", importer.Name, file, err)
+	}
+
+	gpaths, err := utils.LoadGPaths(useTemporal)
+	if err != nil {
+		return ImportPlan{}, err
+	}
+
+	return planImport(gpaths, imported), nil
+}
+
+// ApplyImport adds the gpaths of the plan to the goto-paths file and returns the number of gpaths added.
+// The gpaths that conflict with changes made after the plan are skipped.
+func ApplyImport(plan ImportPlan, useTemporal bool) (int, error) {
+	if len(plan.Added) == 0 {
+		return 0, nil
+	}
+
+	unlock, err := utils.LockGPaths(useTemporal)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	gpaths, err := utils.LoadGPaths(useTemporal)
+	if err != nil {
+		return 0, err
+	}
+
+	imported := slices.Clone(gpaths)
+	for _, gp := range plan.Added {
+		if !containsPathOrAbbreviation(imported, gp) {
+			imported = append(imported, gp)
+		}
+	}
+
+	added := len(imported) - len(gpaths)
+	if added == 0 {
+		return 0, nil
+	}
+	return added, saveChange(fmt.Sprintf("import %v paths", added), gpaths, imported, useTemporal)
+}
+
+// planImport validates the imported paths against the gpaths: the invalid paths and the paths
+// that are already in the gpaths are skipped, and an abbreviation is generated for the paths
+// without one or with an abbreviation already used.
+func planImport(gpaths []gpath.GotoPath, imported []ImportedPath) ImportPlan {
+	plan := ImportPlan{Added: []gpath.GotoPath{}, Conflicts: []ImportIssue{}, Skipped: []ImportIssue{}}
+
+	// The best scored paths first, they get the shortest abbreviations
+	sort.SliceStable(imported, func(i, j int) bool {
+		return imported[i].Score > imported[j].Score
+	})
+
+	abbvs := map[string]bool{}
+	paths := map[string]string{}
+	for _, gp := range gpaths {
+		abbvs[gp.Abbreviation] = true
+		paths[gp.ExpandedPath()] = gp.Abbreviation
+	}
+
+	for _, imp := range imported {
+		path, err := gpath.ValidPath(imp.Path)
+		if err != nil {
+			plan.Skipped = append(plan.Skipped, ImportIssue{Path: imp.Path, Reason: err.Error()})
+			continue
+		}
+
+		if abbv, exists := paths[path]; exists {
+			plan.Conflicts = append(plan.Conflicts, ImportIssue{
+				Path:   path,
+				Reason: fmt.Sprintf("skipped, the path already exists with the abbreviation \"%s\"", abbv),
+			})
+			continue
+		}
+
+		abbv, err := gpath.ValidNewAbbreviation(imp.Abbreviation)
+		if err != nil || abbvs[abbv] {
+			generated, genErr := uniqueAbbreviation(path, abbvs)
+			if genErr != nil {
+				plan.Skipped = append(plan.Skipped, ImportIssue{Path: path, Reason: genErr.Error()})
+				continue
+			}
+			if err == nil {
+				plan.Conflicts = append(plan.Conflicts, ImportIssue{
+					Path:   path,
+					Reason: fmt.Sprintf("the abbreviation \"%s\" already exists, renamed to \"%s\"", abbv, generated),
+				})
+			}
+			abbv = generated
+		}
+
+		abbvs[abbv] = true
+		paths[path] = abbv
+		plan.Added = append(plan.Added, gpath.GotoPath{Path: path, Abbreviation: abbv})
+	}
+
+	return plan
+}
+
+// uniqueAbbreviation generates an abbreviation from the base name of the path that is not in abbvs
+// (e.g. "projects", "projects-2", "projects-3"...). It fails if the abbreviation is not valid as a new
+// abbreviation (e.g. it doesn't match the abbreviation_pattern setting).
+func uniqueAbbreviation(path string, abbvs map[string]bool) (string, error) {
+	base := strings.ToLower(strings.Join(strings.Fields(filepath.Base(path)), "-"))
+	if base == "" || base == "/" || base == "." || base == string(filepath.Separator) {
+		base = "root"
+	}
+	if _, err := strconv.Atoi(base); err == nil {
+		base = "d" + base
+	}
+
+	abbv := base
+	for n := 2; abbvs[abbv]; n++ {
+		abbv = base + "-" + strconv.Itoa(n)
+	}
+
+	valid, err := gpath.ValidNewAbbreviation(abbv)
+	if err != nil {
+		return "", fmt.Errorf("can't generate an abbreviation for \"%s\": %v", path, err)
+	}
+	return valid, nil
+}
+
+// isTerminal checks if the file is a terminal (e.g. the stdin without a pipe)
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
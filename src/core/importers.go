@@ -0,0 +1,151 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Line of a bashmarks file: export DIR_name="/the/path"
+var bashmarksLineRegexp = regexp.MustCompile(`^export DIR_([A-Za-z0-9_]+)=(.*)$`)
+
+// The importers of goto
+func init() {
+	RegisterImporter(Importer{
+		Name:        "zoxide",
+		Description: "The output of \"zoxide query -ls\" (score and path by line), read from stdin by default",
+		DefaultFile: func() string { return "-" },
+		Parse:       parseZoxide,
+	})
+
+	RegisterImporter(Importer{
+		Name:        "autojump",
+		Description: "The autojump.txt file of autojump (weight and path separated by a tab)",
+		DefaultFile: func() string { return filepath.Join(dataHome(), "autojump", "autojump.txt") },
+		Parse:       parseAutojump,
+	})
+
+	RegisterImporter(Importer{
+		Name:        "z",
+		Description: "The data file of z (path|rank|time by line)",
+		DefaultFile: func() string { return envOrHome("_Z_DATA", ".z") },
+		Parse:       parseRankedPipes,
+	})
+
+	RegisterImporter(Importer{
+		Name:        "fasd",
+		Description: "The data file of fasd (path|rank|time by line)",
+		DefaultFile: func() string { return envOrHome("_FASD_DATA", ".fasd") },
+		Parse:       parseRankedPipes,
+	})
+
+	RegisterImporter(Importer{
+		Name:        "bashmarks",
+		Description: "The .sdirs file of bashmarks (export DIR_name=\"path\" by line)",
+		DefaultFile: func() string { return envOrHome("SDIRS", ".sdirs") },
+		Parse:       parseBashmarks,
+	})
+}
+
+// parseZoxide parses the lines "  score /the/path" of "zoxide query -ls"
+func parseZoxide(r io.Reader) ([]ImportedPath, error) {
+	return parseLines(r, func(line string) (ImportedPath, error) {
+		score, path, found := strings.Cut(line, " ")
+		if !found {
+			return ImportedPath{}, fmt.Errorf("expected a score and a path")
+		}
+		return scoredPath(strings.TrimSpace(path), score)
+	})
+}
+
+// parseAutojump parses the lines "weight\t/the/path" of autojump.txt
+func parseAutojump(r io.Reader) ([]ImportedPath, error) {
+	return parseLines(r, func(line string) (ImportedPath, error) {
+		weight, path, found := strings.Cut(line, "\t")
+		if !found {
+			return ImportedPath{}, fmt.Errorf("expected a weight and a path separated by a tab")
+		}
+		return scoredPath(path, weight)
+	})
+}
+
+// parseRankedPipes parses the lines "/the/path|rank|time" of z and fasd
+func parseRankedPipes(r io.Reader) ([]ImportedPath, error) {
+	return parseLines(r, func(line string) (ImportedPath, error) {
+		fields := strings.Split(line, "|")
+		if len(fields) < 3 {
+			return ImportedPath{}, fmt.Errorf("expected path|rank|time")
+		}
+
+		// The path can contain "|", the rank and the time are the last fields
+		path := strings.Join(fields[:len(fields)-2], "|")
+		return scoredPath(path, fields[len(fields)-2])
+	})
+}
+
+// parseBashmarks parses the lines export DIR_name="/the/path" of bashmarks
+func parseBashmarks(r io.Reader) ([]ImportedPath, error) {
+	return parseLines(r, func(line string) (ImportedPath, error) {
+		match := bashmarksLineRegexp.FindStringSubmatch(line)
+		if match == nil {
+			return ImportedPath{}, fmt.Errorf("expected export DIR_name=\"path\"")
+		}
+
+		// bashmarks saves the paths inside the home as $HOME/...
+		path := os.ExpandEnv(strings.Trim(match[2], `"'`))
+		return ImportedPath{Path: path, Abbreviation: match[1]}, nil
+	})
+}
+
+// parseLines parses each non-empty line with parse, the errors include the line number
+func parseLines(r io.Reader, parse func(line string) (ImportedPath, error)) ([]ImportedPath, error) {
+	paths := []ImportedPath{}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		p, err := parse(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		paths = append(paths, p)
+	}
+
+	return paths, scanner.Err()
+}
+
+// scoredPath returns the ImportedPath with the score parsed
+func scoredPath(path, score string) (ImportedPath, error) {
+	s, err := strconv.ParseFloat(strings.TrimSpace(score), 64)
+	if err != nil {
+		return ImportedPath{}, fmt.Errorf("invalid score \"%s\"", score)
+	}
+	return ImportedPath{Path: path, Score: s}, nil
+}
+
+// envOrHome returns the value of the environment variable or the file in the home directory
+func envOrHome(env, file string) string {
+	if value := os.Getenv(env); value != "" {
+		return value
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, file)
+}
+
+// dataHome returns the XDG data directory
+func dataHome() string {
+	if value := os.Getenv("XDG_DATA_HOME"); value != "" {
+		return value
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "share")
+}
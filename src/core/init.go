@@ -3,20 +3,51 @@ package core
 import (
 	"fmt"
 	"goto/src/utils"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 )
 
-// InitializeConfig sets up the configuration directory, binary, and shell alias.
-func InitializeConfig(msgChan chan<- Message) error {
+// The markers of the block added by init to the rc files (uninstall removes the block)
+const (
+	initBlockStart = "# >>> goto >>>"
+	initBlockEnd   = "# <<< goto <<<"
+
+	// The comment written by init before the source command
+	initSourceComment = "#Aliases to use goto:"
+)
+
+// CompletionShells are the shells with a completion script
+var CompletionShells = []string{"bash", "zsh", "fish"}
+
+// CompletionGenerator writes the completion script of goto for the shell (bash, zsh or fish)
+type CompletionGenerator func(shell string, w io.Writer) error
+
+// InitOptions are the options of the initialization
+type InitOptions struct {
+	// The shell to configure (bash, zsh, fish...), if it is empty the shell is detected from $SHELL
+	Shell string
+
+	// The generator of the completion scripts, if it is nil the completion scripts are not generated
+	Completion CompletionGenerator
+}
+
+// InitializeConfig sets up the configuration directory, binary, shell script and completion scripts.
+func InitializeConfig(msgChan chan<- Message, opts InitOptions) error {
 	notifier := NewNotifier(msgChan)
 	goos := runtime.GOOS
 	if goos == "windows" {
 		return fmt.Errorf("initialization is not supported on Windows")
 	}
 
+	integration, err := getShellIntegration(opts.Shell)
+	if err != nil {
+		return err
+	}
+
 	configDir := utils.GetConfigDir()
 	if err := ensureConfigDir(configDir, notifier); err != nil {
 		return err
@@ -27,12 +58,22 @@ func InitializeConfig(msgChan chan<- Message) error {
 		return err
 	}
 
-	aliasFile, err := generateAliasFile(configDir, exePath, notifier)
+	completionDir, err := generateCompletionFiles(opts.Completion, notifier)
+	if err != nil {
+		return err
+	}
+
+	scriptFile, err := generateShellScript(configDir, integration, exePath, completionDir, notifier)
 	if err != nil {
 		return err
 	}
 
-	return configureShell(aliasFile, notifier)
+	return configureShell(integration, scriptFile, notifier)
+}
+
+// getCompletionDir returns the directory of the completion scripts
+func getCompletionDir() string {
+	return filepath.Join(utils.GetConfigDir(), "completions")
 }
 
 func ensureConfigDir(configDir string, notifier *Notifier) error {
@@ -76,87 +117,193 @@ func installBinary(configDir string, notifier *Notifier) (string, error) {
 	return exePath, nil
 }
 
-func generateAliasFile(configDir, exePath string, notifier *Notifier) (string, error) {
-	aliasContent := fmt.Sprintf(`#!/bin/bash
-GOTO_FILE="%s"
-#GOTO FUNC
-goto() {
-    OUTPUT=$("$GOTO_FILE" $@)
-
-    #If the return "2", the program return a gpath successfully
-    if [ $? -eq 2 ]; then
-        cd "$OUTPUT"
-        echo "Go to:" $OUTPUT
-    elif [ $? -eq 1 ]; then # If error exit with status 1
-        echo "$OUTPUT"
-        return 1
-    else
-        echo "$OUTPUT"
-    fi
-}
+// generateCompletionFiles writes the completion scripts in the completions directory and returns it
+// (empty if there is not a generator). For fish, the script is also written in its completions directory.
+func generateCompletionFiles(completion CompletionGenerator, notifier *Notifier) (string, error) {
+	if completion == nil {
+		return "", nil
+	}
 
-#cd is change by goto function
-alias cd="goto"
-alias cdt="goto -t"
-`, exePath)
+	completionDir := getCompletionDir()
+	if err := os.MkdirAll(completionDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create completions dir: %w", err)
+	}
+
+	for _, shell := range CompletionShells {
+		var sb strings.Builder
+		if err := completion(shell, &sb); err != nil {
+			return "", fmt.Errorf("failed to generate the %s completion: %w", shell, err)
+		}
+
+		files := []string{filepath.Join(completionDir, "goto."+shell)}
+		if shell == "fish" && strings.Contains(os.Getenv("SHELL"), "fish") {
+			if homeDir, err := os.UserHomeDir(); err == nil {
+				fishDir := filepath.Join(homeDir, ".config", "fish", "completions")
+				if err := os.MkdirAll(fishDir, 0755); err == nil {
+					files = append(files, filepath.Join(fishDir, "goto.fish"))
+				}
+			}
+		}
+
+		for _, file := range files {
+			if err := os.WriteFile(file, []byte(sb.String()), 0644); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	notifier.Success("Generated the completion scripts in %s", completionDir)
+	return completionDir, nil
+}
 
-	aliasFile := filepath.Join(configDir, "alias.sh")
-	err := os.WriteFile(aliasFile, []byte(aliasContent), 0644)
+// generateShellScript writes the script of the shell in the config directory
+func generateShellScript(configDir string, integration ShellIntegration, exePath, completionDir string, notifier *Notifier) (string, error) {
+	var sb strings.Builder
+	err := integration.Render(&sb, newShellScriptData(exePath, completionFile(integration, completionDir)))
 	if err != nil {
 		return "", err
 	}
-	notifier.Success("Generated %s", aliasFile)
-	return aliasFile, nil
+
+	scriptFile := filepath.Join(configDir, integration.ScriptFile)
+	if err := os.WriteFile(scriptFile, []byte(sb.String()), 0644); err != nil {
+		return "", err
+	}
+	notifier.Success("Generated %s", scriptFile)
+	return scriptFile, nil
 }
 
-func configureShell(aliasFile string, notifier *Notifier) error {
-	shell := os.Getenv("SHELL")
-	var shellRC string
+// configureShell adds the goto block (that loads the script) to the rc file of the shell. If the block
+// already exists, it is updated in place. The rc file is backed up before any change.
+func configureShell(integration ShellIntegration, scriptFile string, notifier *Notifier) error {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return err
 	}
 
-	if strings.Contains(shell, "zsh") {
-		shellRC = filepath.Join(homeDir, ".zshrc")
-	} else if strings.Contains(shell, "bash") {
-		shellRC = filepath.Join(homeDir, ".bashrc")
-	} else if strings.Contains(shell, "fish") {
-		shellRC = filepath.Join(homeDir, ".config", "fish", "config.fish")
-	} else if strings.Contains(shell, "dash") {
-		shellRC = filepath.Join(homeDir, ".profile")
-	} else if strings.Contains(shell, "tcsh") {
-		shellRC = filepath.Join(homeDir, ".tcshrc")
-	} else if strings.Contains(shell, "csh") {
-		shellRC = filepath.Join(homeDir, ".cshrc")
-	} else if strings.Contains(shell, "ksh") {
-		shellRC = filepath.Join(homeDir, ".kshrc")
-	} else if strings.HasSuffix(shell, "/sh") || shell == "sh" {
-		shellRC = filepath.Join(homeDir, ".profile")
-	} else {
-		return fmt.Errorf("unsupported shell: %s", shell)
-	}
-
-	f, err := os.OpenFile(shellRC, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
-	if err != nil {
+	shellRC := filepath.Join(homeDir, integration.RCFile)
+	if err := os.MkdirAll(filepath.Dir(shellRC), 0755); err != nil {
 		return err
 	}
-	defer f.Close()
 
 	content, err := os.ReadFile(shellRC)
-	if err != nil {
+	if err != nil && !os.IsNotExist(err) {
 		return err
 	}
 
-	sourceCmd := fmt.Sprintf("source %s", aliasFile)
-	if strings.Contains(string(content), sourceCmd) {
-		notifier.Info("Alias already sourced in %s", shellRC)
+	block := []string{initBlockStart, initSourceComment, integration.SourceCommand(scriptFile), initBlockEnd}
+	updated := updateInitBlock(string(content), block, getScriptFiles())
+
+	if updated == string(content) {
+		notifier.Info("The goto block of %s is already up to date", shellRC)
 		return nil
-	} else {
-		if _, err := f.WriteString(fmt.Sprintf("\n#Aliases to use goto:\n%s\n", sourceCmd)); err != nil {
-			return err
+	}
+
+	perm := os.FileMode(0644)
+	backup := os.DevNull
+	if info, err := os.Stat(shellRC); err == nil {
+		perm = info.Mode().Perm()
+
+		backup = rcBackupPath(shellRC)
+		if err := os.WriteFile(backup, content, perm); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", shellRC, err)
 		}
-		notifier.Success("Added source command to %s\nPlease restart your terminal or run 'source %s' to activate goto.", shellRC, shellRC)
-		return nil
+		notifier.Info("Backed up %s to %s", shellRC, backup)
+	}
+
+	if err := os.WriteFile(shellRC, []byte(updated), perm); err != nil {
+		return err
+	}
+
+	notifier.Info("%s", strings.TrimSuffix(utils.UnifiedDiff(backup, shellRC, string(content), updated), "\n"))
+	notifier.Success("Updated the goto block of %s\nPlease restart your terminal or run '%s' to activate goto.", shellRC, integration.SourceCommand(shellRC))
+	return nil
+}
+
+// rcBackupPath returns the copy of the rc file made before changing it (uninstall removes it)
+func rcBackupPath(rcFile string) string {
+	return rcFile + ".goto-backup"
+}
+
+// getScriptFiles returns the scripts of all the shells in the config directory
+func getScriptFiles() []string {
+	files := []string{}
+	for _, name := range ShellNames() {
+		file := filepath.Join(utils.GetConfigDir(), shellIntegrations[name].ScriptFile)
+		if !slices.Contains(files, file) {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// updateInitBlock replaces the first goto block of the content of a rc file with the block, and removes
+// the other goto blocks and the source commands written by older versions of init. If there is not
+// a block (or an old source command to replace), the block is added at the end.
+func updateInitBlock(content string, block []string, scriptFiles []string) string {
+	lines := strings.Split(content, "\n")
+	updated := []string{}
+	replaced := false
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		switch {
+		case line == initBlockStart:
+			// Skip until the end of the block (if the end is missing, only the start is replaced)
+			if end := findInitBlockEnd(lines, i); end >= 0 {
+				i = end
+			}
+
+		case isSourceCommand(line, lastLine(updated), scriptFiles):
+			// Also the comment written by the older versions of init
+			if len(updated) > 0 && strings.TrimSpace(updated[len(updated)-1]) == initSourceComment {
+				updated = updated[:len(updated)-1]
+			}
+
+		default:
+			updated = append(updated, lines[i])
+			continue
+		}
+
+		// The first block (or old source command) is replaced in place
+		if !replaced {
+			updated = append(updated, block...)
+			replaced = true
+		}
+	}
+
+	if !replaced {
+		// Add the block at the end, after a blank line
+		if len(updated) > 0 && updated[len(updated)-1] == "" {
+			updated = updated[:len(updated)-1]
+		}
+		if len(updated) > 0 {
+			updated = append(updated, "")
+		}
+		updated = append(append(updated, block...), "")
+	}
+
+	return strings.Join(updated, "\n")
+}
+
+// findInitBlockEnd returns the line of the end of the goto block that starts in the line start, or -1 if
+// it is missing (a block can't contain another start)
+func findInitBlockEnd(lines []string, start int) int {
+	for i := start + 1; i < len(lines); i++ {
+		switch strings.TrimSpace(lines[i]) {
+		case initBlockEnd:
+			return i
+		case initBlockStart:
+			return -1
+		}
+	}
+	return -1
+}
+
+// lastLine returns the last line ("" if there are no lines)
+func lastLine(lines []string) string {
+	if len(lines) == 0 {
+		return ""
 	}
+	return lines[len(lines)-1]
 }
@@ -0,0 +1,171 @@
+package core
+
+import (
+	"fmt"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"strconv"
+	"time"
+)
+
+// HistoryItem is an operation of the journal of the goto-paths file
+type HistoryItem struct {
+	Operation string
+	Time      time.Time
+	Diff      gpath.Diff
+
+	// The operation was undone (it can be redone)
+	Undone bool
+}
+
+// saveChange overwrites the goto-paths file with the gpaths after the operation and records the
+// operation in the journal, so it can be undone. The before gpaths must be a copy of the loaded gpaths.
+// The lock of the goto-paths file must be held.
+func saveChange(operation string, before, after []gpath.GotoPath, useTemporal bool) error {
+	return saveLinkedChange(operation, "", before, after, useTemporal)
+}
+
+// saveLinkedChange is saveChange for one of the changes of an operation over both files, link is the id
+// shared by the entries of both journals (see gpath.JournalEntry). The locks of both files must be held.
+func saveLinkedChange(operation, link string, before, after []gpath.GotoPath, useTemporal bool) error {
+	if err := utils.UpdateGPaths(useTemporal, after); err != nil {
+		return err
+	}
+
+	journal, err := utils.LoadJournal(useTemporal)
+	if err == nil {
+		journal.Record(operation, link, before, after, time.Now())
+		err = utils.UpdateJournal(useTemporal, journal)
+	}
+	if err != nil {
+		return fmt.Errorf("the change was saved, but it can't be recorded to undo it: %v", err)
+	}
+
+	return nil
+}
+
+// newJournalLink returns a new id to link the entries of an operation over both files
+func newJournalLink() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+// Undo reverts the last operation over the goto-paths file and returns its description.
+// It fails if the goto-paths file was changed after the operation without goto.
+func Undo(useTemporal bool) (string, error) {
+	return moveJournal(useTemporal, true)
+}
+
+// Redo applies again the last undone operation over the goto-paths file and returns its description.
+// It fails if the goto-paths file was changed after the undo without goto.
+func Redo(useTemporal bool) (string, error) {
+	return moveJournal(useTemporal, false)
+}
+
+// journalMove is an undo or a redo of the last entry of a journal, before saving it
+type journalMove struct {
+	useTemporal bool
+	journal     gpath.Journal
+	entry       gpath.JournalEntry
+
+	// The gpaths of the file after the move
+	gpaths []gpath.GotoPath
+}
+
+// moveJournal undoes or redoes an operation of the journal. If the operation also changed the other
+// file (e.g. a promote), its entry in the journal of the other file is undone or redone too.
+func moveJournal(useTemporal bool, undo bool) (string, error) {
+	// Both files are locked, always the goto-paths file first
+	unlockPermanent, err := utils.LockGPaths(false)
+	if err != nil {
+		return "", err
+	}
+	defer unlockPermanent()
+
+	unlockTemporal, err := utils.LockGPaths(true)
+	if err != nil {
+		return "", err
+	}
+	defer unlockTemporal()
+
+	move, err := loadJournalMove(useTemporal, undo)
+	if err != nil {
+		return "", err
+	}
+	moves := []journalMove{move}
+
+	if move.entry.Link != "" {
+		action, other := "redo", "goto-paths"
+		if undo {
+			action = "undo"
+		}
+		if !useTemporal {
+			other = "temporal"
+		}
+
+		linked, err := loadJournalMove(!useTemporal, undo)
+		if err != nil {
+			return "", fmt.Errorf("can't %s %s, it also changed the %s file: %v", action, move.entry.Operation, other, err)
+		}
+		if linked.entry.Link != move.entry.Link {
+			return "", fmt.Errorf("can't %s %s, it also changed the %s file and it has other changes after it (%s them first with the flag of that file)",
+				action, move.entry.Operation, other, action)
+		}
+		moves = append(moves, linked)
+	}
+
+	for _, m := range moves {
+		if err := utils.UpdateGPaths(m.useTemporal, m.gpaths); err != nil {
+			return "", err
+		}
+	}
+	for _, m := range moves {
+		if err := utils.UpdateJournal(m.useTemporal, m.journal); err != nil {
+			return "", err
+		}
+	}
+
+	return move.entry.Operation, nil
+}
+
+// loadJournalMove loads the journal and the gpaths of the file and undoes or redoes its last entry
+func loadJournalMove(useTemporal bool, undo bool) (journalMove, error) {
+	current, err := utils.LoadGPaths(useTemporal)
+	if err != nil {
+		return journalMove{}, err
+	}
+
+	journal, err := utils.LoadJournal(useTemporal)
+	if err != nil {
+		return journalMove{}, err
+	}
+
+	move := journalMove{useTemporal: useTemporal, journal: journal}
+	if undo {
+		move.entry, err = move.journal.Undo(current)
+		move.gpaths = move.entry.Before
+	} else {
+		move.entry, err = move.journal.Redo(current)
+		move.gpaths = move.entry.After
+	}
+	return move, err
+}
+
+// History returns the operations of the journal of the goto-paths file, the oldest first.
+func History(useTemporal bool) ([]HistoryItem, error) {
+	journal, err := utils.LoadJournal(useTemporal)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]HistoryItem, 0, len(journal.Entries))
+	for i, e := range journal.Entries {
+		items = append(items, HistoryItem{
+			Operation: e.Operation,
+			Time:      time.Unix(e.Timestamp, 0),
+			Diff:      gpath.DiffGPaths(e.Before, e.After),
+			Undone:    i >= journal.Position,
+		})
+	}
+
+	return items, nil
+}
@@ -0,0 +1,321 @@
+package core
+
+import (
+	"fmt"
+	"goto/src/config"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// The layers of gpaths used to resolve an argument when the temporal flag is not passed
+const (
+	// The gpaths of the temporal file
+	LayerTemporal = "temporal"
+
+	// The gpaths of the project files (.goto.json) from the working directory upward
+	LayerProject = "project"
+
+	// The gpaths of the goto-paths file and the ones inherited from the base profiles
+	LayerPermanent = "permanent"
+)
+
+// Environment variable with the order of the layers (e.g. "permanent,project,temporal"),
+// a layer that is not in the list is not used. It overrides the resolve_order setting.
+const RESOLVE_ORDER_ENV_VAR = config.ENV_PREFIX + "RESOLVE_ORDER"
+
+// DefaultLayerOrder is the order of the layers if it is not configured
+var DefaultLayerOrder = []string{LayerTemporal, LayerProject, LayerPermanent}
+
+// LayeredGPath is a gpath of a layer
+type LayeredGPath struct {
+	gpath.GotoPath
+
+	// The index to use the gpath (with the temporal flag for the temporal layer) or -1 if it hasn't any
+	// (a project gpath with the same path or abbreviation that a permanent gpath)
+	Index int
+
+	Layer string
+
+	// The layer with a gpath with the same path or abbreviation that is used instead of this one
+	ShadowedBy string
+}
+
+// Collision is an abbreviation that is in more than one layer with different paths
+type Collision struct {
+	Abbreviation string
+
+	// The gpath used (the one of the first layer) and the ones shadowed by it
+	Used     LayeredGPath
+	Shadowed []LayeredGPath
+}
+
+func (c *Collision) String() string {
+	layers := []string{}
+	for _, s := range c.Shadowed {
+		layers = append(layers, fmt.Sprintf("%s (%s)", s.Layer, s.ExpandedPath()))
+	}
+	return fmt.Sprintf("the abbreviation \"%s\" is also in the %s layer, the %s one is used (%s)",
+		c.Abbreviation, strings.Join(layers, " and "), c.Used.Layer, c.Used.ExpandedPath())
+}
+
+// ParseLayerOrder parses a list of layers separated by commas (e.g. "temporal,project,permanent")
+func ParseLayerOrder(order string) ([]string, error) {
+	layers := []string{}
+	for _, layer := range strings.Split(order, ",") {
+		layer = strings.ToLower(strings.TrimSpace(layer))
+
+		switch {
+		case layer != LayerTemporal && layer != LayerProject && layer != LayerPermanent:
+			return nil, fmt.Errorf("invalid layer \"%s\" (valid layers: %s, %s, %s)", layer, LayerTemporal, LayerProject, LayerPermanent)
+		case slices.Contains(layers, layer):
+			return nil, fmt.Errorf("the layer \"%s\" is repeated", layer)
+		}
+		layers = append(layers, layer)
+	}
+	return layers, nil
+}
+
+func init() {
+	config.RegisterValidator("resolve_order", func(order string) error {
+		_, err := ParseLayerOrder(order)
+		return err
+	})
+}
+
+// GetLayerOrder returns the order of the layers: the one of the resolve_order setting (or the
+// RESOLVE_ORDER_ENV_VAR environment variable) or, if it is not set, DefaultLayerOrder
+func GetLayerOrder() ([]string, error) {
+	order := config.Get().ResolveOrder
+	if order == "" {
+		return slices.Clone(DefaultLayerOrder), nil
+	}
+
+	layers, err := ParseLayerOrder(order)
+	if err != nil {
+		return nil, fmt.Errorf("%v in the resolve_order setting", err)
+	}
+	return layers, nil
+}
+
+// ListLayers returns the gpaths of the layers in the order of resolution, with the shadowed gpaths
+// (a gpath is shadowed if a previous layer has a gpath with the same path or abbreviation).
+// The default gpaths of the temporal file (see seededTemporalGPath) are not in the layers.
+func ListLayers() ([]LayeredGPath, error) {
+	order, err := GetLayerOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	// The permanent layer is loaded once, the indexes of the project layer follow it
+	var permanent []LayeredGPath
+	if slices.Contains(order, LayerPermanent) || slices.Contains(order, LayerProject) {
+		if permanent, err = loadLayer(LayerPermanent, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	layers := map[string][]LayeredGPath{}
+	for _, layer := range order {
+		if layer == LayerPermanent {
+			layers[layer] = permanent
+			continue
+		}
+		if layers[layer], err = loadLayer(layer, permanent); err != nil {
+			return nil, err
+		}
+	}
+
+	if temporal, ok := layers[LayerTemporal]; ok {
+		layers[LayerTemporal] = slices.DeleteFunc(temporal, seededTemporalGPath())
+	}
+
+	entries := []LayeredGPath{}
+	for _, layer := range order {
+		for _, entry := range layers[layer] {
+			for _, previous := range entries {
+				if previous.ShadowedBy == "" && previous.Layer != layer &&
+					(previous.Abbreviation == entry.Abbreviation || previous.ExpandedPath() == entry.ExpandedPath()) {
+					entry.ShadowedBy = previous.Layer
+					break
+				}
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// loadLayer loads the gpaths of a layer with their indexes, the project layer uses the gpaths of the
+// permanent layer (already loaded) to index them
+func loadLayer(layer string, permanent []LayeredGPath) ([]LayeredGPath, error) {
+	switch layer {
+	case LayerTemporal:
+		gpaths, err := utils.LoadGPaths(true)
+		return newLayeredGPaths(gpaths, LayerTemporal), err
+
+	case LayerPermanent:
+		gpaths, err := utils.LoadGPaths(false)
+		if err != nil {
+			return nil, err
+		}
+
+		inherited, err := loadBaseProfilesGPaths(gpaths)
+		if err != nil {
+			return nil, err
+		}
+		return newLayeredGPaths(append(gpaths, inherited...), LayerPermanent), nil
+
+	default:
+		// All the project gpaths are loaded, the ones that are listed (see ListResolvablePaths)
+		// have the index of the list
+		permanentGPaths := make([]gpath.GotoPath, 0, len(permanent))
+		for _, entry := range permanent {
+			permanentGPaths = append(permanentGPaths, entry.GotoPath)
+		}
+
+		listed, err := loadProjectGPaths(permanentGPaths)
+		if err != nil {
+			return nil, err
+		}
+
+		all, err := loadProjectGPaths(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := newLayeredGPaths(all, LayerProject)
+		for i := range entries {
+			entries[i].Index = -1
+			for j := range listed {
+				if listed[j].Source == entries[i].Source && listed[j].Abbreviation == entries[i].Abbreviation {
+					entries[i].Index = len(permanent) + j
+					break
+				}
+			}
+		}
+		return entries, nil
+	}
+}
+
+// seededTemporalGPath returns a function that checks if a temporal gpath is one of the default gpaths
+// written when the temporal file is created (e.g. "h" and "config"), they are only used with the temporal flag
+func seededTemporalGPath() func(LayeredGPath) bool {
+	defaults, err := gpath.DefaultGPaths(utils.GetFilePath(true))
+	if err != nil {
+		return func(LayeredGPath) bool { return false }
+	}
+
+	return func(entry LayeredGPath) bool {
+		return slices.ContainsFunc(defaults, func(d gpath.GotoPath) bool {
+			return d.Path == entry.Path && d.Abbreviation == entry.Abbreviation
+		})
+	}
+}
+
+// newLayeredGPaths returns the gpaths of the layer with their indexes in the list
+func newLayeredGPaths(gpaths []gpath.GotoPath, layer string) []LayeredGPath {
+	entries := make([]LayeredGPath, 0, len(gpaths))
+	for i, gp := range gpaths {
+		entries = append(entries, LayeredGPath{GotoPath: gp, Index: i, Layer: layer})
+	}
+	return entries
+}
+
+// OutputEntry returns the output representation of the gpath with its layer
+func (e LayeredGPath) OutputEntry() utils.OutputEntry {
+	entry := utils.NewOutputEntry(e.Index, e.GotoPath)
+	entry.Layer = e.Layer
+	entry.ShadowedBy = e.ShadowedBy
+	return entry
+}
+
+// lookupLayered returns the gpath of the index or the abbreviation, and the collision if the
+// abbreviation is in more than one layer. The indexes of the temporal layer are only used if
+// temporalIndexes (when only the temporal layer is used), because they are used with the temporal flag.
+func lookupLayered(entries []LayeredGPath, arg string, temporalIndexes bool) (*LayeredGPath, *Collision, bool) {
+	if index, err := strconv.Atoi(arg); err == nil {
+		for i := range entries {
+			if entries[i].Index == index && (entries[i].Layer != LayerTemporal || temporalIndexes) {
+				return &entries[i], nil, true
+			}
+		}
+		return nil, nil, false
+	}
+
+	var used *LayeredGPath
+	var collision *Collision
+	for i := range entries {
+		if entries[i].Abbreviation != arg {
+			continue
+		}
+
+		switch {
+		case used == nil && entries[i].ShadowedBy == "":
+			used = &entries[i]
+		case used != nil && entries[i].ExpandedPath() != used.ExpandedPath():
+			if collision == nil {
+				collision = &Collision{Abbreviation: arg, Used: *used}
+			}
+			collision.Shadowed = append(collision.Shadowed, entries[i])
+		}
+	}
+
+	return used, collision, used != nil
+}
+
+// activeGPaths returns the gpaths that are not shadowed
+func activeGPaths(entries []LayeredGPath) []LayeredGPath {
+	active := []LayeredGPath{}
+	for _, entry := range entries {
+		if entry.ShadowedBy == "" {
+			active = append(active, entry)
+		}
+	}
+	return active
+}
+
+// SearchLayers searches a gpath by path or abbreviation in the layers (in the order of resolution)
+// and returns the first one that is not shadowed.
+func SearchLayers(pathArg, abbvArg string) (*LayeredGPath, error) {
+	entries, err := ListLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	active := activeGPaths(entries)
+	gpaths := make([]gpath.GotoPath, 0, len(active))
+	for _, entry := range active {
+		gpaths = append(gpaths, entry.GotoPath)
+	}
+
+	idx, _, err := findPath(gpaths, pathArg, abbvArg)
+	if err != nil {
+		return nil, err
+	}
+	return &active[idx], nil
+}
+
+// SearchLayersByTags returns the gpaths of the layers (that are not shadowed) with all the tags (matchAll)
+// or any of them, and error if none is found.
+func SearchLayersByTags(tags []string, matchAll bool) ([]LayeredGPath, error) {
+	entries, err := ListLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	found := []LayeredGPath{}
+	for _, entry := range activeGPaths(entries) {
+		if entry.HasTags(tags, matchAll) {
+			found = append(found, entry)
+		}
+	}
+
+	if len(found) == 0 {
+		return nil, fmt.Errorf("doesn't exist a path with the tags \"%s\"", strings.Join(tags, ", "))
+	}
+	return found, nil
+}
@@ -9,3 +9,9 @@ import (
 func ListPaths(useTemporal bool) ([]gpath.GotoPath, error) {
 	return utils.LoadGPaths(useTemporal)
 }
+
+// ListResolvablePaths returns the list of goto paths used to resolve a path: the goto paths
+// followed by the ones inherited from the base profiles and the project files.
+func ListResolvablePaths(useTemporal bool) ([]gpath.GotoPath, error) {
+	return loadResolvableGPaths(useTemporal)
+}
@@ -0,0 +1,366 @@
+package core
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/term"
+)
+
+// ErrPickerCancelled is returned when the picker is closed without selecting a path.
+var ErrPickerCancelled = errors.New("no path was selected")
+
+// Keys of the picker
+const (
+	keyCtrlC     = 3
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlU     = 21
+	keyBackspace = 127
+	keyCtrlH     = 8
+	keyEscape    = 27
+	keyEnter     = '\r'
+	keyNewLine   = '\n'
+)
+
+// escapeTimeout is how long the picker waits for the next byte of an escape sequence, the bytes of
+// a key like Up (ESC [ A) arrive together, so an Escape without more bytes in that time is a lone Escape
+const escapeTimeout = 50 * time.Millisecond
+
+// Escape sequences used to draw the picker
+const (
+	seqAltScreenOn  = "\x1b[?1049h"
+	seqAltScreenOff = "\x1b[?1049l"
+	seqClear        = "\x1b[H\x1b[2J"
+	seqReverse      = "\x1b[7m"
+	seqDim          = "\x1b[2m"
+	seqReset        = "\x1b[0m"
+)
+
+// PickerItem is an entry of the interactive picker.
+type PickerItem struct {
+	Label string
+	Path  string
+}
+
+// Picker is an interactive, incremental filter over a list of items.
+type Picker struct {
+	items    []PickerItem
+	query    []rune
+	filtered []int
+	cursor   int
+
+	// Size of the terminal (rows and columns)
+	Height int
+	Width  int
+}
+
+// NewPicker creates a new Picker with all the items selectable.
+func NewPicker(items []PickerItem, query string) *Picker {
+	p := &Picker{items: items, query: []rune(query), Height: 24, Width: 80}
+	p.filter()
+	return p
+}
+
+// Query returns the current filter of the picker.
+func (p *Picker) Query() string {
+	return string(p.query)
+}
+
+// Filtered returns the items that match the current query.
+func (p *Picker) Filtered() []PickerItem {
+	filtered := make([]PickerItem, 0, len(p.filtered))
+	for _, i := range p.filtered {
+		filtered = append(filtered, p.items[i])
+	}
+	return filtered
+}
+
+// Selected returns the item under the cursor and false if no item matches the query.
+func (p *Picker) Selected() (PickerItem, bool) {
+	if len(p.filtered) == 0 {
+		return PickerItem{}, false
+	}
+	return p.items[p.filtered[p.cursor]], true
+}
+
+// filter keeps the items whose label contains all the words of the query (case-insensitive).
+func (p *Picker) filter() {
+	words := strings.Fields(strings.ToLower(string(p.query)))
+
+	p.filtered = p.filtered[:0]
+	for i, item := range p.items {
+		label := strings.ToLower(item.Label)
+		matches := true
+		for _, w := range words {
+			if !strings.Contains(label, w) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			p.filtered = append(p.filtered, i)
+		}
+	}
+
+	if p.cursor >= len(p.filtered) {
+		p.cursor = max(len(p.filtered)-1, 0)
+	}
+}
+
+// Run reads the keys from in and draws the picker in out until a path is selected (Enter)
+// or the picker is cancelled (Esc or Ctrl-C).
+func (p *Picker) Run(in io.Reader, out io.Writer) (string, error) {
+	done := make(chan struct{})
+	defer close(done)
+	keys := readKeys(in, done)
+
+	for {
+		p.Render(out)
+
+		r, ok := <-keys
+		if !ok {
+			return "", ErrPickerCancelled
+		}
+
+		switch r {
+		case keyEnter, keyNewLine:
+			if item, ok := p.Selected(); ok {
+				return item.Path, nil
+			}
+
+		case keyCtrlC:
+			return "", ErrPickerCancelled
+
+		case keyEscape:
+			// A lone Escape closes the picker, otherwise it is an arrow key (ESC [ A / ESC O B...)
+			// or other escape sequence (ignored)
+			final, ok := readEscapeSequence(keys)
+			if !ok {
+				return "", ErrPickerCancelled
+			}
+			switch final {
+			case 'A':
+				p.move(-1)
+			case 'B':
+				p.move(1)
+			}
+
+		case keyCtrlP:
+			p.move(-1)
+
+		case keyCtrlN:
+			p.move(1)
+
+		case keyBackspace, keyCtrlH:
+			if len(p.query) > 0 {
+				p.query = p.query[:len(p.query)-1]
+				p.filter()
+			}
+
+		case keyCtrlU:
+			p.query = p.query[:0]
+			p.filter()
+
+		default:
+			if unicode.IsPrint(r) {
+				p.query = append(p.query, r)
+				p.cursor = 0
+				p.filter()
+			}
+		}
+	}
+}
+
+// readKeys reads the runes of in in a goroutine, so the picker can wait for a key with a timeout. The
+// channel is closed at the end of the input, the goroutine ends with it or when done is closed.
+func readKeys(in io.Reader, done <-chan struct{}) <-chan rune {
+	keys := make(chan rune)
+	go func() {
+		defer close(keys)
+		reader := bufio.NewReader(in)
+		for {
+			r, _, err := reader.ReadRune()
+			if err != nil {
+				return
+			}
+			select {
+			case keys <- r:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return keys
+}
+
+// nextKey waits escapeTimeout for the next key, false if no key arrives in that time or the input ends.
+func nextKey(keys <-chan rune) (rune, bool) {
+	select {
+	case r, ok := <-keys:
+		return r, ok
+	case <-time.After(escapeTimeout):
+		return 0, false
+	}
+}
+
+// readEscapeSequence reads the rest of an escape sequence after ESC byte by byte and returns its final
+// byte (e.g. 'A' of ESC [ A), false if it is a lone Escape.
+func readEscapeSequence(keys <-chan rune) (rune, bool) {
+	r, ok := nextKey(keys)
+	if !ok {
+		return 0, false
+	}
+	if r != '[' && r != 'O' {
+		// ESC and a key (Alt+key), it doesn't have a final byte
+		return 0, true
+	}
+
+	// The parameters of a CSI sequence (e.g. ESC [ 1 ; 5 A) are between '0' and '?'
+	for {
+		if r, ok = nextKey(keys); !ok {
+			return 0, false
+		}
+		if r < '0' || r > '?' {
+			return r, true
+		}
+	}
+}
+
+// move moves the cursor n positions (negative is up) without leaving the list.
+func (p *Picker) move(n int) {
+	p.cursor = min(max(p.cursor+n, 0), max(len(p.filtered)-1, 0))
+}
+
+// Render draws the query, the filtered items and a preview of the selected directory.
+func (p *Picker) Render(out io.Writer) {
+	var sb strings.Builder
+	line := func(format string, args ...interface{}) {
+		s := fmt.Sprintf(format, args...)
+		if runes := []rune(s); len(runes) > p.Width && p.Width > 0 {
+			s = string(runes[:p.Width])
+		}
+		sb.WriteString(s + "\r\n")
+	}
+
+	sb.WriteString(seqClear)
+	line("> %s", string(p.query))
+	line("%s%d/%d (Enter: go, Esc: cancel, Up/Down: move)%s", seqDim, len(p.filtered), len(p.items), seqReset)
+
+	// Half of the screen for the list, the rest for the preview
+	listHeight := max((p.Height-4)/2, 1)
+	start := max(p.cursor-listHeight+1, 0)
+	for i := start; i < len(p.filtered) && i < start+listHeight; i++ {
+		label := p.items[p.filtered[i]].Label
+		if i == p.cursor {
+			line("%s> %s%s", seqReverse, label, seqReset)
+		} else {
+			line("  %s", label)
+		}
+	}
+
+	if item, ok := p.Selected(); ok {
+		line("%s── %s%s", seqDim, item.Path, seqReset)
+		for _, entry := range previewDir(item.Path, max(p.Height-listHeight-4, 0)) {
+			line("  %s", entry)
+		}
+	}
+
+	io.WriteString(out, sb.String())
+}
+
+// previewDir returns the first n entries of the directory (directories end with "/").
+func previewDir(path string, n int) []string {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return []string{"(can't read the directory: " + err.Error() + ")"}
+	}
+
+	preview := []string{}
+	for _, e := range entries {
+		if len(preview) == n {
+			break
+		}
+		name := e.Name()
+		if e.IsDir() {
+			name += "/"
+		}
+		preview = append(preview, name)
+	}
+	return preview
+}
+
+// PickerItems returns the items of the picker: the gpaths of the goto-paths file and of the temporal file.
+func PickerItems() ([]PickerItem, error) {
+	items := []PickerItem{}
+
+	gpaths, err := utils.LoadGPaths(false)
+	if err != nil {
+		return nil, err
+	}
+	for i := range gpaths {
+		items = append(items, PickerItem{Label: fmt.Sprintf("%v - %s", i, gpaths[i].String()), Path: gpaths[i].ExpandedPath()})
+	}
+
+	temporal, err := utils.LoadGPaths(true)
+	if err != nil {
+		return nil, err
+	}
+	for i := range temporal {
+		items = append(items, PickerItem{Label: fmt.Sprintf("[t] %v - %s", i, temporal[i].String()), Path: temporal[i].ExpandedPath()})
+	}
+
+	return items, nil
+}
+
+// PickPath opens the interactive picker in the terminal (/dev/tty, so it works when the
+// stdout is captured by the shell) and returns the selected path.
+func PickPath(query string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return "", fmt.Errorf("the interactive picker is not supported on Windows")
+	}
+
+	items, err := PickerItems()
+	if err != nil {
+		return "", err
+	}
+
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", fmt.Errorf("the interactive picker needs a terminal: %v", err)
+	}
+	defer tty.Close()
+
+	// Set the raw mode and restore the previous state of the terminal at the end
+	fd := int(tty.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", fmt.Errorf("can't set the terminal in raw mode: %v", err)
+	}
+	defer term.Restore(fd, state)
+
+	picker := NewPicker(items, query)
+	if width, height, err := term.GetSize(fd); err == nil {
+		picker.Height, picker.Width = height, width
+	}
+
+	io.WriteString(tty, seqAltScreenOn)
+	defer io.WriteString(tty, seqAltScreenOff)
+
+	selected, err := picker.Run(tty, tty)
+	if err != nil {
+		return "", err
+	}
+
+	// A portable path whose environment variable is not set is not expanded
+	return gpath.ExpandPathStrict(selected)
+}
@@ -0,0 +1,215 @@
+package core
+
+import (
+	"fmt"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Profile is the information of a profile of goto-paths.
+type Profile struct {
+	Name    string
+	Base    string
+	Current bool
+}
+
+// ListProfiles returns all the profiles, marking the profile in use.
+func ListProfiles() ([]Profile, error) {
+	names, err := utils.GetProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := utils.LoadProfilesConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]Profile, 0, len(names))
+	for _, name := range names {
+		profiles = append(profiles, Profile{
+			Name:    name,
+			Base:    config.Bases[name],
+			Current: name == utils.GetCurrentProfile(),
+		})
+	}
+	return profiles, nil
+}
+
+// CreateProfile creates a new profile with the default goto-paths. If base is not empty,
+// the paths that are not in the profile are resolved in the base profile.
+func CreateProfile(name, base string) error {
+	if err := utils.ValidProfileName(name); err != nil {
+		return err
+	}
+
+	if utils.ProfileExists(name) {
+		return fmt.Errorf("the profile \"%s\" already exists", name)
+	}
+
+	if base != "" {
+		if !utils.ProfileExists(base) {
+			return fmt.Errorf("the base profile \"%s\" doesn't exist", base)
+		}
+
+		config, err := utils.LoadProfilesConfig()
+		if err != nil {
+			return err
+		}
+
+		config.Bases[name] = base
+		if err := utils.UpdateProfilesConfig(config); err != nil {
+			return err
+		}
+	}
+
+	return gpath.CreateGotoPathsFile(utils.GetProfileFilePath(name))
+}
+
+// CopyProfile creates the profile dst with the goto-paths of the profile src.
+func CopyProfile(src, dst string) error {
+	if err := utils.ValidProfileName(dst); err != nil {
+		return err
+	}
+
+	if utils.ProfileExists(dst) {
+		return fmt.Errorf("the profile \"%s\" already exists", dst)
+	}
+
+	gpaths, err := utils.LoadProfileGPaths(src)
+	if err != nil {
+		return fmt.Errorf("can't load the profile \"%s\": %v", src, err)
+	}
+
+	dstFile := utils.GetProfileFilePath(dst)
+	if err := os.MkdirAll(filepath.Dir(dstFile), 0755); err != nil {
+		return err
+	}
+
+	return gpath.SaveGPathsFile(gpaths, dstFile)
+}
+
+// SwitchProfile selects the profile used when neither the profile flag nor the GOTO_PROFILE
+// environment variable are set.
+func SwitchProfile(name string) error {
+	if !utils.ProfileExists(name) {
+		return fmt.Errorf("the profile \"%s\" doesn't exist", name)
+	}
+
+	config, err := utils.LoadProfilesConfig()
+	if err != nil {
+		return err
+	}
+
+	config.Current = name
+	return utils.UpdateProfilesConfig(config)
+}
+
+// DeleteProfile deletes a profile. The default profile and the base of other profiles can't be deleted.
+// If the profile was the selected one, the default profile is selected.
+func DeleteProfile(name string) error {
+	if name == utils.DEFAULT_PROFILE {
+		return fmt.Errorf("the default profile can't be deleted")
+	}
+
+	if !utils.ProfileExists(name) {
+		return fmt.Errorf("the profile \"%s\" doesn't exist", name)
+	}
+
+	config, err := utils.LoadProfilesConfig()
+	if err != nil {
+		return err
+	}
+
+	for profile, base := range config.Bases {
+		if base == name {
+			return fmt.Errorf("the profile \"%s\" is the base of the profile \"%s\"", name, profile)
+		}
+	}
+
+	delete(config.Bases, name)
+	if config.Current == name {
+		config.Current = ""
+	}
+
+	if err := utils.UpdateProfilesConfig(config); err != nil {
+		return err
+	}
+
+	// The files derived from the file of the profile (its last valid copy, lock, journal, backups...) and its
+	// automatic backups are removed too, so a new profile with the same name doesn't inherit them
+	file := utils.GetProfileFilePath(name)
+	derived, err := profileDerivedFiles(name, file)
+	if err != nil {
+		return err
+	}
+	for _, f := range derived {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Remove(file)
+}
+
+// profileDerivedFiles returns the files "<file>.*" of the file of the profile and its automatic backups
+func profileDerivedFiles(name, file string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(file))
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), filepath.Base(file)+".") {
+			files = append(files, filepath.Join(filepath.Dir(file), e.Name()))
+		}
+	}
+
+	generations, err := gpath.ListGenerations(utils.GetBackupsDir(), name)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range generations {
+		files = append(files, g.File)
+	}
+	return files, nil
+}
+
+// loadBaseProfilesGPaths returns the goto-paths of the bases of the profile in use (in order),
+// without the goto-paths whose path or abbreviation is already in gpaths.
+func loadBaseProfilesGPaths(gpaths []gpath.GotoPath) ([]gpath.GotoPath, error) {
+	chain, err := utils.GetProfileChain(utils.GetCurrentProfile())
+	if err != nil {
+		return nil, err
+	}
+
+	inherited := []gpath.GotoPath{}
+	for _, base := range chain[1:] {
+		baseGPaths, err := utils.LoadProfileGPaths(base)
+		if err != nil {
+			return nil, fmt.Errorf("can't load the base profile \"%s\": %v", base, err)
+		}
+
+		for _, gp := range baseGPaths {
+			if !containsPathOrAbbreviation(gpaths, gp) && !containsPathOrAbbreviation(inherited, gp) {
+				gp.Source = utils.GetProfileFilePath(base)
+				inherited = append(inherited, gp)
+			}
+		}
+	}
+
+	return inherited, nil
+}
+
+// containsPathOrAbbreviation checks if any of the gpaths has the same path or abbreviation that gp.
+func containsPathOrAbbreviation(gpaths []gpath.GotoPath, gp gpath.GotoPath) bool {
+	for i := range gpaths {
+		if gpaths[i].ExpandedPath() == gp.ExpandedPath() || gpaths[i].Abbreviation == gp.Abbreviation {
+			return true
+		}
+	}
+	return false
+}
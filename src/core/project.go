@@ -0,0 +1,53 @@
+package core
+
+import (
+	"goto/src/gpath"
+	"goto/src/utils"
+	"os"
+)
+
+// loadProjectGPaths returns the goto-paths of the project files found from the working directory
+// upward. The gpaths already defined win over the project ones (same path or abbreviation) and a
+// nearer project file wins over a farther one.
+func loadProjectGPaths(gpaths []gpath.GotoPath) ([]gpath.GotoPath, error) {
+	projects, err := utils.LoadProjectGPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	project := []gpath.GotoPath{}
+	for _, projectGPaths := range projects {
+		for _, gp := range projectGPaths {
+			if !containsPathOrAbbreviation(gpaths, gp) && !containsPathOrAbbreviation(project, gp) {
+				project = append(project, gp)
+			}
+		}
+	}
+
+	return project, nil
+}
+
+// addProjectPath adds a new path to the nearest project file (from the working directory upward).
+// If there is not any project file, it is created in the working directory. The file is locked while
+// it is changed and it is written atomically (see gpath.SaveProjectFile).
+func addProjectPath(gp gpath.GotoPath) error {
+	projectFile, err := utils.GetNearestProjectFile()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := gpath.LockFile(projectFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	gpaths := []gpath.GotoPath{}
+	if _, err := os.Stat(projectFile); err == nil {
+		if gpaths, err = gpath.LoadProjectFile(projectFile); err != nil {
+			return err
+		}
+	}
+
+	return gpath.SaveProjectFile(append(gpaths, gp), projectFile)
+}
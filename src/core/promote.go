@@ -0,0 +1,104 @@
+package core
+
+import (
+	"goto/src/gpath"
+	"goto/src/utils"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// PromotePath moves a temporal gpath (identified by its abbreviation or index) to the goto-paths file,
+// without its expiration time and its session. Returns the promoted gpath.
+func PromotePath(identifier string) (*gpath.GotoPath, error) {
+	return moveGPath(identifier, true, func(gp *gpath.GotoPath) error {
+		*gp = gp.Permanent()
+		return nil
+	})
+}
+
+// DemotePath moves a gpath of the goto-paths file (identified by its abbreviation or index) to the temporal
+// file. It expires after the ttl (if it isn't 0) and, if onlySession, it is only seen from the current shell
+// session. Returns the demoted gpath.
+func DemotePath(identifier string, ttl time.Duration, onlySession bool) (*gpath.GotoPath, error) {
+	return moveGPath(identifier, false, func(gp *gpath.GotoPath) error {
+		return setTemporalLifetime(gp, ttl, onlySession)
+	})
+}
+
+// moveGPath moves a gpath from the temporal file to the goto-paths file (fromTemporal) or the other way,
+// prepare changes the gpath before adding it. Each file records its change in its journal, linked to
+// the other one, so undoing (or redoing) any of them reverts both.
+func moveGPath(identifier string, fromTemporal bool, prepare func(*gpath.GotoPath) error) (*gpath.GotoPath, error) {
+	// Both files are locked, always the goto-paths file first
+	unlockPermanent, err := utils.LockGPaths(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockPermanent()
+
+	unlockTemporal, err := utils.LockGPaths(true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockTemporal()
+
+	source, err := utils.LoadGPaths(fromTemporal)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := utils.LoadGPaths(!fromTemporal)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, gp, err := findGPath(source, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	moved := *gp
+	if err := prepare(&moved); err != nil {
+		return nil, err
+	}
+
+	sourceAfter := slices.Delete(slices.Clone(source), idx, idx+1)
+	targetAfter := append(slices.Clone(target), moved)
+
+	// Validate both files before changing anything
+	if err := gpath.CheckRepeatedItems(targetAfter); err != nil {
+		return nil, err
+	}
+	if err := gpath.CheckRepeatedItems(sourceAfter); err != nil {
+		return nil, err
+	}
+
+	operation := "demote "
+	if fromTemporal {
+		operation = "promote "
+	}
+
+	// The gpath is added before removing it, so it isn't lost if something fails
+	link := newJournalLink()
+	if err := saveLinkedChange(operation+moved.String(), link, target, targetAfter, !fromTemporal); err != nil {
+		return nil, err
+	}
+	if err := saveLinkedChange(operation+gp.String(), link, source, sourceAfter, fromTemporal); err != nil {
+		return nil, err
+	}
+
+	return &moved, nil
+}
+
+// findGPath finds a gpath by its index or its abbreviation (an abbreviation can't be a number)
+func findGPath(gpaths []gpath.GotoPath, identifier string) (int, *gpath.GotoPath, error) {
+	if idx, err := strconv.Atoi(identifier); err == nil {
+		if err := gpath.IsValidIndex(len(gpaths), identifier); err != nil {
+			return -1, nil, err
+		}
+		return idx, &gpaths[idx], nil
+	}
+
+	return findPath(gpaths, "", identifier)
+}
@@ -1,38 +1,213 @@
 package core
 
 import (
-	"bufio"
 	"fmt"
 	"goto/src/gpath"
 	"goto/src/utils"
 	"os"
+	"slices"
+)
+
+// Strategies to solve the conflicts of a merge
+const (
+	// Keep the gpath of the goto-paths file
+	MergeKeepCurrent = "keep-current"
+
+	// Replace the gpath of the goto-paths file with the gpath of the backup
+	MergeTakeBackup = "take-backup"
+
+	// Add the gpath of the backup with other abbreviation (when the abbreviation is used by other path)
+	MergeRename = "rename"
 
-	"github.com/bytedance/sonic"
+	// Ask how to solve each conflict
+	MergeInteractive = "interactive"
 )
 
+// MergeStrategies are the valid strategies of a merge
+var MergeStrategies = []string{MergeKeepCurrent, MergeTakeBackup, MergeRename, MergeInteractive}
+
+// MergeConflict is a gpath of the backup with the same path or abbreviation that a gpath of the goto-paths file
+type MergeConflict struct {
+	Current gpath.GotoPath
+	Backup  gpath.GotoPath
+}
+
+// RestoreOptions are the options of a restore
+type RestoreOptions struct {
+	// Merge the backup with the goto-paths file instead of overwriting it
+	Merge bool
+
+	// The strategy to solve the conflicts of the merge
+	Strategy string
+
+	// Compute the changes without writing the goto-paths file
+	DryRun bool
+
+	// Called by the interactive strategy to solve each conflict, it returns
+	// MergeKeepCurrent, MergeTakeBackup or MergeRename
+	Resolve func(conflict MergeConflict) (string, error)
+}
+
 // RestoreGPaths restores goto paths from inputPath.
 func RestoreGPaths(inputPath string, useTemporal bool) error {
+	_, err := RestoreGPathsWithOptions(inputPath, RestoreOptions{}, useTemporal)
+	return err
+}
+
+// RestoreGPathsWithOptions restores (or merges) the goto paths from inputPath and returns
+// the changes made in the goto-paths file. With the DryRun option nothing is written.
+func RestoreGPathsWithOptions(inputPath string, opts RestoreOptions, useTemporal bool) (gpath.Diff, error) {
+	backup, err := loadBackupFile(inputPath)
+	if err != nil {
+		return gpath.Diff{}, err
+	}
+
+	unlock, err := utils.LockGPaths(useTemporal)
+	if err != nil {
+		return gpath.Diff{}, err
+	}
+	defer unlock()
+
+	restored := backup
+	current, err := utils.LoadGPaths(useTemporal)
+	if err != nil && (opts.Merge || opts.DryRun) {
+		return gpath.Diff{}, err
+	}
+
+	if opts.Merge {
+		if restored, err = mergeGPaths(current, backup, opts); err != nil {
+			return gpath.Diff{}, err
+		}
+	}
+
+	diff := gpath.DiffGPaths(current, restored)
+	if opts.DryRun {
+		return diff, gpath.CheckRepeatedItems(restored)
+	}
+
+	operation := "restore " + inputPath
+	if opts.Merge {
+		operation += " (merge)"
+	}
+	return diff, saveChange(operation, current, restored, useTemporal)
+}
+
+// loadBackupFile loads the gpaths of a backup file (of any schema version)
+func loadBackupFile(inputPath string) ([]gpath.GotoPath, error) {
 	info, err := os.Stat(inputPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if info.IsDir() {
-		return fmt.Errorf("the input can't be a directory")
+		return nil, fmt.Errorf("the input can't be a directory")
 	}
 
-	file, err := os.Open(inputPath)
+	data, err := os.ReadFile(inputPath)
 	if err != nil {
-		return fmt.Errorf("cant open the backup of config file: %v", err)
+		return nil, fmt.Errorf("cant open the backup of config file: %v", err)
 	}
-	defer file.Close()
 
-	reader := bufio.NewReader(file)
+	// The backups can be of any schema version (e.g. a legacy bare array)
+	gpaths, _, err := gpath.DecodeGPaths(data)
+	if err != nil {
+		return nil, fmt.Errorf("cant parse the backup of config file: %v", err)
+	}
+
+	return gpaths, nil
+}
 
-	var gpaths []gpath.GotoPath
-	if err := sonic.ConfigFastest.NewDecoder(reader).Decode(&gpaths); err != nil {
-		return fmt.Errorf("cant parse the backup of config file: %v", err)
+// mergeGPaths adds the gpaths of the backup to the current gpaths. A gpath of the backup with the same
+// path or abbreviation that a current gpath is a conflict, solved with the strategy of the options.
+func mergeGPaths(current, backup []gpath.GotoPath, opts RestoreOptions) ([]gpath.GotoPath, error) {
+	strategy := opts.Strategy
+	if strategy == "" {
+		strategy = MergeKeepCurrent
 	}
+	if !slices.Contains(MergeStrategies, strategy) {
+		return nil, fmt.Errorf("invalid merge strategy \"%s\" (valid strategies: %v)", strategy, MergeStrategies)
+	}
+	if strategy == MergeInteractive && opts.Resolve == nil {
+		return nil, fmt.Errorf("the interactive merge strategy needs a terminal")
+	}
+
+	merged := make([]gpath.GotoPath, len(current))
+	copy(merged, current)
+
+	for _, b := range backup {
+		idx := conflictIndex(merged, b)
+		if idx == -1 {
+			merged = append(merged, b)
+			continue
+		}
+
+		if merged[idx].Equal(b) {
+			continue
+		}
+
+		choice := strategy
+		if strategy == MergeInteractive {
+			var err error
+			if choice, err = opts.Resolve(MergeConflict{Current: merged[idx], Backup: b}); err != nil {
+				return nil, err
+			}
+		}
 
-	return utils.UpdateGPaths(useTemporal, gpaths)
+		switch choice {
+		case MergeKeepCurrent:
+
+		case MergeTakeBackup:
+			// If other gpath has the abbreviation of the backup, the gpath of the backup is renamed
+			sameAbbv := func(gp gpath.GotoPath) bool { return gp.Abbreviation == b.Abbreviation }
+			if abbvIdx := slices.IndexFunc(merged, sameAbbv); abbvIdx != -1 && abbvIdx != idx {
+				abbv, err := uniqueAbbreviation(b.Path, usedAbbreviations(merged))
+				if err != nil {
+					return nil, err
+				}
+				b.Abbreviation = abbv
+			}
+			merged[idx] = b
+
+		case MergeRename:
+			// Only a gpath with other path can be added with other abbreviation
+			if merged[idx].Path == b.Path {
+				continue
+			}
+			abbv, err := uniqueAbbreviation(b.Path, usedAbbreviations(merged))
+			if err != nil {
+				return nil, err
+			}
+			b.Abbreviation = abbv
+			merged = append(merged, b)
+
+		default:
+			return nil, fmt.Errorf("invalid merge strategy \"%s\"", choice)
+		}
+	}
+
+	return merged, nil
+}
+
+// conflictIndex returns the index of the gpath with the same path (or else, the same abbreviation) that gp
+func conflictIndex(gpaths []gpath.GotoPath, gp gpath.GotoPath) int {
+	for i := range gpaths {
+		if gpaths[i].Path == gp.Path {
+			return i
+		}
+	}
+	for i := range gpaths {
+		if gpaths[i].Abbreviation == gp.Abbreviation {
+			return i
+		}
+	}
+	return -1
+}
+
+// usedAbbreviations returns the abbreviations of the gpaths
+func usedAbbreviations(gpaths []gpath.GotoPath) map[string]bool {
+	abbvs := make(map[string]bool, len(gpaths))
+	for _, gp := range gpaths {
+		abbvs[gp.Abbreviation] = true
+	}
+	return abbvs
 }
@@ -1,37 +1,181 @@
 package core
 
 import (
+	"fmt"
 	"goto/src/gpath"
 	"goto/src/utils"
 	"path/filepath"
+	"strings"
 )
 
-// ResolvePath resolves the target path based on arguments and flags.
+// AmbiguousMatchError is returned when the best fuzzy matches of an argument have the same score.
+type AmbiguousMatchError struct {
+	Arg     string
+	Matches []gpath.Match
+}
+
+func (e *AmbiguousMatchError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "the argument \"%s\" is ambiguous, the candidates are:", e.Arg)
+	for _, m := range e.Matches {
+		fmt.Fprintf(&sb, "\n%v - %s", m.Index, m.GotoPath.String())
+	}
+	return sb.String()
+}
+
+// Resolution is a path resolved from the arguments
+type Resolution struct {
+	Path string
+
+	// The layer of the gpath used (empty if the path is not a gpath)
+	Layer string
+
+	// The collision of the abbreviation used, if it is in more than one layer
+	Collision *Collision
+}
+
+// ResolvePath resolves the target path based on arguments and flags (see Resolve).
 func ResolvePath(args []string, onlyDirectory bool, useTemporal bool) (string, error) {
+	resolution, err := Resolve(args, onlyDirectory, useTemporal)
+	return resolution.Path, err
+}
+
+// Resolve resolves the target path based on arguments and flags.
+//
+// Without the temporal flag, the abbreviations are searched in the layers (temporal, project and permanent
+// by default, see GetLayerOrder) and the first one is used. The indexes are the ones of the list of the
+// goto-paths: the goto-paths of the profile, the ones inherited from its bases and the ones of the project files.
+// With the temporal flag, only the temporal goto-paths are used.
+//
+// The args are joined as a path, if the first segment is an index or an abbreviation the rest
+// of the segments are resolved relative to that gpath (e.g. "docs 2024" or "docs/2024").
+//
+// If the argument is not an index, an abbreviation or a directory, the goto-paths are
+// fuzzy matched (see gpath.FuzzyMatch) and, if nothing matches, the path of the navigation
+// history with the best frecency score is used.
+func Resolve(args []string, onlyDirectory bool, useTemporal bool) (Resolution, error) {
 	path := filepath.Join(args...)
 
+	// If only directory flag is passed, check if is a directory
 	if onlyDirectory {
-		// If only directory flag is passed, check if is a directory
-		if err := gpath.ValidPathVar(&path); err != nil {
-			return "", err
-		}
-	} else {
-		// Load the config file
-		gpathsList, err := utils.LoadGPaths(useTemporal)
+		err := gpath.ValidPathVar(&path)
+		return Resolution{Path: path}, err
+	}
+
+	// Load the gpaths of the layers
+	entries, err := loadResolutionGPaths(useTemporal)
+	if err != nil {
+		return Resolution{}, err
+	}
+
+	// Check if is a index or an abbreviation
+	if entry, collision, ok := lookupLayered(entries, path, useTemporal); ok {
+		resolved, err := entry.ResolvedPath()
 		if err != nil {
-			return "", err
+			return Resolution{}, err
+		}
+		return Resolution{Path: resolved, Layer: entry.Layer, Collision: collision}, nil
+	}
+
+	// Check if the first segment is an index or an abbreviation (e.g. "docs/2024/reports")
+	if segments := strings.Split(filepath.ToSlash(path), "/"); !filepath.IsAbs(path) && len(segments) > 1 {
+		if entry, collision, ok := lookupLayered(entries, segments[0], useTemporal); ok {
+			resolved, err := entry.ResolvedPath()
+			if err != nil {
+				return Resolution{}, err
+			}
+			subPath := filepath.Join(append([]string{resolved}, segments[1:]...)...)
+			if err := gpath.ValidPathVar(&subPath); err != nil {
+				return Resolution{}, err
+			}
+			return Resolution{Path: subPath, Layer: entry.Layer, Collision: collision}, nil
 		}
+	}
+
+	// If it is not, check if is a directory
+	if err := gpath.ValidPathVar(&path); err != nil {
+		path, err := resolveApproximate(activeGPaths(entries), args, err)
+		return Resolution{Path: path}, err
+	}
+	return Resolution{Path: path}, nil
+}
+
+// loadResolutionGPaths loads the gpaths used to resolve an argument: the ones of the layers
+// or, with the temporal flag, the temporal ones
+func loadResolutionGPaths(useTemporal bool) ([]LayeredGPath, error) {
+	if useTemporal {
+		return loadLayer(LayerTemporal, nil)
+	}
+	return ListLayers()
+}
+
+// loadResolvableGPaths loads the gpaths used to resolve a path: the goto-paths file (or the temporal
+// file) followed by the goto-paths inherited from the bases of the profile in use and the goto-paths
+// of the project files found from the working directory upward.
+func loadResolvableGPaths(useTemporal bool) ([]gpath.GotoPath, error) {
+	gpathsList, err := utils.LoadGPaths(useTemporal)
+	if err != nil || useTemporal {
+		return gpathsList, err
+	}
+
+	inherited, err := loadBaseProfilesGPaths(gpathsList)
+	if err != nil {
+		return nil, err
+	}
+	gpathsList = append(gpathsList, inherited...)
+
+	project, err := loadProjectGPaths(gpathsList)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(gpathsList, project...), nil
+}
 
-		// Check if is a index or an abbreviation
-		var isIndexOrAbbv bool
-		path, isIndexOrAbbv = gpath.GetPathFromIndexOrAbbreviation(gpathsList, path)
+// resolveApproximate resolves the args with a fuzzy match of the gpaths and, if nothing
+// matches, with the navigation history. If neither match, notFoundErr is returned.
+func resolveApproximate(entries []LayeredGPath, args []string, notFoundErr error) (string, error) {
+	terms := strings.FieldsFunc(filepath.Join(args...), func(r rune) bool {
+		return r == filepath.Separator || r == '/'
+	})
 
-		// If it is not, check if is a directory
-		if !isIndexOrAbbv {
-			if err := gpath.ValidPathVar(&path); err != nil {
-				return "", err
+	gpathsList := make([]gpath.GotoPath, 0, len(entries))
+	for _, entry := range entries {
+		gpathsList = append(gpathsList, entry.GotoPath)
+	}
+
+	// The matches have the indexes of the gpaths (not the position in the layers)
+	matches := gpath.FuzzyMatch(gpathsList, terms)
+	for i := range matches {
+		matches[i].Index = entries[matches[i].Index].Index
+	}
+	if gpath.IsAmbiguous(matches) {
+		return "", &AmbiguousMatchError{Arg: strings.Join(args, " "), Matches: matches}
+	}
+
+	if len(matches) > 0 {
+		return matches[0].GotoPath.ResolvedPath()
+	}
+
+	// If it is not a gpath, check the navigation history
+	if match, ok := frecencyMatch(terms); ok {
+		return match, nil
+	}
+
+	return "", notFoundErr
+}
+
+// ResolvedEntry returns the output representation of a resolved path. If the path is
+// not a gpath, the index is -1 and only the path is set.
+func ResolvedEntry(path string, useTemporal bool) utils.OutputEntry {
+	entries, err := loadResolutionGPaths(useTemporal)
+	if err == nil {
+		for _, entry := range activeGPaths(entries) {
+			if entry.MatchesPath(path) {
+				return entry.OutputEntry()
 			}
 		}
 	}
-	return path, nil
+
+	return utils.NewOutputEntry(-1, gpath.GotoPath{Path: path})
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"goto/src/gpath"
 	"goto/src/utils"
+	"strings"
 )
 
 // SearchPath searches for a path by Path or Abbreviation.
@@ -20,13 +21,13 @@ func SearchPath(pathArg, abbvArg string, useTemporal bool) (int, *gpath.GotoPath
 // findPath is an internal helper to find the index and path in a slice of GotoPath.
 func findPath(gpaths []gpath.GotoPath, pathArg, abbvArg string) (int, *gpath.GotoPath, error) {
 	if pathArg != "" {
-		path, err := gpath.ValidPath(pathArg)
+		path, err := gpath.ValidPath(gpath.ExpandPath(pathArg))
 		if err != nil {
 			return -1, nil, err
 		}
 
 		for i := range gpaths {
-			if gpaths[i].Path == path {
+			if gpaths[i].MatchesPath(path) {
 				return i, &gpaths[i], nil
 			}
 		}
@@ -49,3 +50,24 @@ func findPath(gpaths []gpath.GotoPath, pathArg, abbvArg string) (int, *gpath.Got
 
 	return -1, nil, fmt.Errorf("no identifier provided")
 }
+
+// SearchByTags searches the paths that have all the tags (matchAll) or any of them.
+// Returns the indexes and the paths, and error if none is found.
+func SearchByTags(tags []string, matchAll bool, useTemporal bool) ([]int, []gpath.GotoPath, error) {
+	gpaths, err := utils.LoadGPaths(useTemporal)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	indexes := gpath.FilterByTags(gpaths, tags, matchAll)
+	if len(indexes) == 0 {
+		return nil, nil, fmt.Errorf("doesn't exist a path with the tags \"%s\"", strings.Join(tags, ", "))
+	}
+
+	found := make([]gpath.GotoPath, 0, len(indexes))
+	for _, i := range indexes {
+		found = append(found, gpaths[i])
+	}
+
+	return indexes, found, nil
+}
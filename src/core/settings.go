@@ -0,0 +1,128 @@
+package core
+
+import (
+	"fmt"
+	"goto/src/config"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// The sources of the value of a setting
+const (
+	SettingSourceDefault = "default"
+	SettingSourceFile    = "file"
+	SettingSourceEnv     = "env"
+)
+
+// Setting is the value in use of a setting and where it comes from
+type Setting struct {
+	config.Key
+
+	// The value in use (the default value if it isn't set)
+	Value string
+
+	// SettingSourceEnv, SettingSourceFile or SettingSourceDefault
+	Source string
+}
+
+// GetSetting returns the value in use of a setting: the one of its environment variable,
+// the one of the config file or its default value (in that order)
+func GetSetting(name string) (Setting, error) {
+	key, err := config.GetKey(name)
+	if err != nil {
+		return Setting{}, err
+	}
+
+	setting := Setting{Key: key, Value: key.Default, Source: SettingSourceDefault}
+	if value := key.Value(config.GetFileSettings()); value != "" {
+		setting.Value, setting.Source = value, SettingSourceFile
+	}
+
+	// An invalid value of the environment variable is ignored (see config.Get)
+	if env := os.Getenv(key.EnvVar()); env != "" && key.Set(&config.Settings{}, env) == nil {
+		setting.Value, setting.Source = key.Value(config.Get()), SettingSourceEnv
+	}
+	return setting, nil
+}
+
+// ListSettings returns the value in use of all the settings (see GetSetting)
+func ListSettings() ([]Setting, error) {
+	settings := []Setting{}
+	for _, key := range config.Keys() {
+		setting, err := GetSetting(key.Name)
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+// SetSetting validates the value of a setting and saves it in the config file ("" to restore its default value).
+// It returns a warning if the value is overridden by its environment variable.
+func SetSetting(name, value string) (string, error) {
+	key, err := config.GetKey(name)
+	if err != nil {
+		return "", err
+	}
+
+	settings := config.GetFileSettings()
+	if err := key.Set(&settings, value); err != nil {
+		return "", fmt.Errorf("invalid value for %s: %v", name, err)
+	}
+
+	if err := config.Update(settings); err != nil {
+		return "", err
+	}
+
+	if os.Getenv(key.EnvVar()) != "" {
+		return fmt.Sprintf("the value is overridden by the environment variable %s", key.EnvVar()), nil
+	}
+	return "", nil
+}
+
+// EditConfigFile opens the config file in the terminal with the editor of the EDITOR (or VISUAL) environment
+// variable (vi or notepad by default), creating it if it doesn't exist, and validates it after the edition.
+func EditConfigFile() error {
+	file := config.GetFilePath()
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		if err := config.SaveFile(config.Settings{}, file); err != nil {
+			return err
+		}
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = os.Getenv("VISUAL")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// The editor can have arguments (e.g. "code --wait")
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], file)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	// The editor uses the terminal (/dev/tty), because the stdout is captured by the shell function
+	if runtime.GOOS != "windows" {
+		tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+		if err != nil {
+			return fmt.Errorf("the editor needs a terminal: %v", err)
+		}
+		defer tty.Close()
+		cmd.Stdin, cmd.Stdout = tty, tty
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error running the editor \"%s\": %v", editor, err)
+	}
+
+	// Reload the settings to check the changes
+	return config.Setup(filepath.Dir(file))
+}
@@ -0,0 +1,326 @@
+package core
+
+import (
+	"fmt"
+	"goto/src/config"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// ShellIntegration is the integration of goto with a shell: the script that defines the goto
+// function (and the cd alias) and the rc file that loads it
+type ShellIntegration struct {
+	Name string
+
+	// Name of the script in the config directory
+	ScriptFile string
+
+	// The rc file of the shell (relative to the home directory)
+	RCFile string
+
+	// The shell of the completion script loaded by the script (empty if there is not completion)
+	Completion string
+
+	// The format of the command that loads the script from the rc file
+	SourceFormat string
+
+	template *template.Template
+}
+
+// ShellScriptData is the data used to render the script of a shell
+type ShellScriptData struct {
+	// The goto binary
+	Exe string
+
+	// The completion script (empty if there is not completion)
+	Completion string
+
+	// The exit status of goto to move to the printed path and the message printed
+	// before it (empty to don't print anything), see the navigate_* settings
+	ExitCode int
+	Message  string
+}
+
+// newShellScriptData returns the data of the script with the settings in use
+func newShellScriptData(exe, completion string) ShellScriptData {
+	settings := config.Get()
+	return ShellScriptData{
+		Exe:        exe,
+		Completion: completion,
+		ExitCode:   settings.ExitCode(),
+		Message:    settings.Message(),
+	}
+}
+
+// SourceCommand returns the command to load the script from the rc file
+func (s ShellIntegration) SourceCommand(file string) string {
+	return fmt.Sprintf(s.SourceFormat, file)
+}
+
+// Render writes the script of the shell
+func (s ShellIntegration) Render(w io.Writer, data ShellScriptData) error {
+	return s.template.Execute(w, data)
+}
+
+// The script for bash, ksh and sh (dash, ash...). All the scripts pass the PID of the shell
+// in GOTO_SESSION, so each shell has its own directory stack
+const posixShellScript = `#!/bin/sh
+GOTO_FILE="{{.Exe}}"
+
+#GOTO FUNC
+goto() {
+    OUTPUT=$(GOTO_SESSION=$$ "$GOTO_FILE" "$@")
+    STATUS=$?
+
+    #If the return "{{.ExitCode}}", the program return a gpath successfully
+    if [ $STATUS -eq {{.ExitCode}} ]; then
+        command cd "$OUTPUT"{{if .Message}} && echo "{{.Message}}$OUTPUT"{{end}}
+    else
+        [ -n "$OUTPUT" ] && echo "$OUTPUT"
+        return $STATUS
+    fi
+}
+
+#cd is change by goto function
+alias cd="goto"
+alias cdt="goto -t"
+{{- if .Completion}}
+
+#Completion of goto
+if [ -n "$BASH_VERSION" ]; then
+    source "{{.Completion}}"
+fi
+{{- end}}
+`
+
+// The script for zsh, the chpwd hook records the directories visited without goto
+const zshShellScript = `GOTO_FILE="{{.Exe}}"
+
+#GOTO FUNC
+goto() {
+    local output ret
+    output=$(GOTO_SESSION=$$ "$GOTO_FILE" "$@")
+    ret=$?
+
+    #If the return "{{.ExitCode}}", the program return a gpath successfully
+    if [ $ret -eq {{.ExitCode}} ]; then
+        _GOTO_VISITED="$output"
+        builtin cd -- "$output"{{if .Message}} && echo "{{.Message}}$output"{{end}}
+    else
+        [ -n "$output" ] && echo "$output"
+        return $ret
+    fi
+}
+
+#Record the directories visited with cd, pushd... in the navigation history
+#(goto already records the directories visited with it)
+_goto_chpwd() {
+    if [ "$PWD" != "$_GOTO_VISITED" ]; then
+        "$GOTO_FILE" record "$PWD" >/dev/null 2>&1
+    fi
+    _GOTO_VISITED=""
+}
+autoload -Uz add-zsh-hook
+add-zsh-hook chpwd _goto_chpwd
+
+#cd is change by goto function
+alias cd="goto"
+alias cdt="goto -t"
+{{- if .Completion}}
+
+#Completion of goto (compinit must be loaded before)
+if (( $+functions[compdef] )); then
+    source "{{.Completion}}"
+fi
+{{- end}}
+`
+
+// The script for fish
+const fishShellScript = `set -g GOTO_FILE "{{.Exe}}"
+
+#GOTO FUNC
+function goto --description 'Move to a goto-path'
+    set -lx GOTO_SESSION $fish_pid
+    set -l output (command "$GOTO_FILE" $argv)
+    set -l ret $status
+
+    #If the return "{{.ExitCode}}", the program return a gpath successfully
+    if test $ret -eq {{.ExitCode}}
+        builtin cd -- "$output"{{if .Message}}; and echo "{{.Message}}$output"{{end}}
+    else
+        test -n "$output"; and printf '%s\n' $output
+        return $ret
+    end
+end
+
+#cd is change by goto function
+function cd --wraps goto --description 'Move to a goto-path'
+    goto $argv
+end
+
+function cdt --wraps goto --description 'Move to a temporal goto-path'
+    goto -t $argv
+end
+{{- if .Completion}}
+
+#Completion of goto
+test -f "{{.Completion}}"; and source "{{.Completion}}"
+{{- end}}
+`
+
+// The script for tcsh and csh, they don't have functions so goto is an alias
+// (chdir is used because cd is an alias of goto)
+const cshShellScript = `set _goto_file = "{{.Exe}}"
+
+#GOTO ALIAS: if the return "{{.ExitCode}}", move to the output, else print it
+alias goto 'setenv GOTO_SESSION $$; set _goto_out = "` + "`" + `$_goto_file \!*` + "`" + `"; set _goto_status = $status; {{if .Message}}if ($_goto_status == {{.ExitCode}}) echo "{{.Message}}$_goto_out[1]"; {{end}}if ($_goto_status == {{.ExitCode}}) chdir "$_goto_out[1]"; if ($_goto_status != {{.ExitCode}}) printf "%s\n" $_goto_out:q'
+
+#cd is change by goto alias
+alias cd 'goto \!*'
+alias cdt 'goto -t \!*'
+`
+
+// The script for PowerShell
+const pwshShellScript = `$env:GOTO_FILE = "{{.Exe}}"
+$env:GOTO_SESSION = $PID
+
+#GOTO FUNC
+function Invoke-Goto {
+    $output = & $env:GOTO_FILE @args
+    $code = $LASTEXITCODE
+
+    #If the return "{{.ExitCode}}", the program return a gpath successfully
+    if ($code -eq {{.ExitCode}}) {
+        Set-Location -LiteralPath "$output"
+        {{- if .Message}}
+        Write-Host "{{.Message}}$output"
+        {{- end}}
+    } else {
+        $output
+    }
+}
+
+function Invoke-GotoTemporal {
+    Invoke-Goto -t @args
+}
+
+#cd is change by goto function
+Set-Alias -Name goto -Value Invoke-Goto -Option AllScope -Scope Global -Force
+Set-Alias -Name cd -Value Invoke-Goto -Option AllScope -Scope Global -Force
+Set-Alias -Name cdt -Value Invoke-GotoTemporal -Option AllScope -Scope Global -Force
+`
+
+// The registered shell integrations by name
+var shellIntegrations = map[string]ShellIntegration{}
+
+func registerShellIntegration(s ShellIntegration, script string) {
+	s.template = template.Must(template.New(s.Name).Parse(script))
+	shellIntegrations[s.Name] = s
+}
+
+func init() {
+	registerShellIntegration(ShellIntegration{Name: "bash", ScriptFile: "alias.sh", RCFile: ".bashrc", Completion: "bash", SourceFormat: "source %s"}, posixShellScript)
+	registerShellIntegration(ShellIntegration{Name: "ksh", ScriptFile: "alias.sh", RCFile: ".kshrc", SourceFormat: ". %s"}, posixShellScript)
+	registerShellIntegration(ShellIntegration{Name: "sh", ScriptFile: "alias.sh", RCFile: ".profile", SourceFormat: ". %s"}, posixShellScript)
+	registerShellIntegration(ShellIntegration{Name: "zsh", ScriptFile: "alias.zsh", RCFile: ".zshrc", Completion: "zsh", SourceFormat: "source %s"}, zshShellScript)
+	registerShellIntegration(ShellIntegration{Name: "fish", ScriptFile: "alias.fish", RCFile: filepath.Join(".config", "fish", "config.fish"), Completion: "fish", SourceFormat: "source %s"}, fishShellScript)
+	registerShellIntegration(ShellIntegration{Name: "tcsh", ScriptFile: "alias.csh", RCFile: ".tcshrc", SourceFormat: "source %s"}, cshShellScript)
+	registerShellIntegration(ShellIntegration{Name: "csh", ScriptFile: "alias.csh", RCFile: ".cshrc", SourceFormat: "source %s"}, cshShellScript)
+	registerShellIntegration(ShellIntegration{Name: "pwsh", ScriptFile: "alias.ps1", RCFile: filepath.Join(".config", "powershell", "Microsoft.PowerShell_profile.ps1"), SourceFormat: ". %s"}, pwshShellScript)
+}
+
+// GetShellIntegration returns the integration of the shell
+func GetShellIntegration(name string) (ShellIntegration, error) {
+	s, ok := shellIntegrations[name]
+	if !ok {
+		return ShellIntegration{}, fmt.Errorf("unsupported shell \"%s\" (valid shells: %s)", name, strings.Join(ShellNames(), ", "))
+	}
+	return s, nil
+}
+
+// ShellNames returns the names of the supported shells (sorted)
+func ShellNames() []string {
+	names := make([]string, 0, len(shellIntegrations))
+	for name := range shellIntegrations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DetectShell returns the name of the shell of the path (usually $SHELL)
+func DetectShell(shell string) (string, error) {
+	base := filepath.Base(shell)
+
+	switch {
+	case strings.Contains(base, "zsh"):
+		return "zsh", nil
+	case strings.Contains(base, "bash"):
+		return "bash", nil
+	case strings.Contains(base, "fish"):
+		return "fish", nil
+	case strings.Contains(base, "tcsh"):
+		return "tcsh", nil
+	case strings.Contains(base, "csh"):
+		return "csh", nil
+	case strings.Contains(base, "pwsh"), strings.Contains(base, "powershell"):
+		return "pwsh", nil
+	case strings.Contains(base, "ksh"):
+		return "ksh", nil
+	case base == "sh", base == "dash", base == "ash":
+		return "sh", nil
+	default:
+		return "", fmt.Errorf("unsupported shell: %s", shell)
+	}
+}
+
+// getShellIntegration returns the integration of the shell or, if it is empty, of the shell in $SHELL
+func getShellIntegration(shell string) (ShellIntegration, error) {
+	if shell == "" {
+		detected, err := DetectShell(os.Getenv("SHELL"))
+		if err != nil {
+			return ShellIntegration{}, fmt.Errorf("%v (use the --shell flag)", err)
+		}
+		shell = detected
+	}
+	return GetShellIntegration(shell)
+}
+
+// PrintShellScript writes the script of the shell for the running binary, without installing anything
+// (e.g. for eval "$(goto init --print zsh)"). The completion script is loaded if init generated it.
+func PrintShellScript(w io.Writer, shell string) error {
+	integration, err := GetShellIntegration(shell)
+	if err != nil {
+		return err
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	exePath, err = filepath.EvalSymlinks(exePath)
+	if err != nil {
+		return err
+	}
+
+	return integration.Render(w, newShellScriptData(exePath, completionFile(integration, getCompletionDir())))
+}
+
+// completionFile returns the completion script of the shell in the completions directory
+// (empty if the shell doesn't have completion or the script doesn't exist)
+func completionFile(integration ShellIntegration, completionDir string) string {
+	if integration.Completion == "" || completionDir == "" {
+		return ""
+	}
+
+	file := filepath.Join(completionDir, "goto."+integration.Completion)
+	if _, err := os.Stat(file); err != nil {
+		return ""
+	}
+	return file
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"fmt"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"os"
+)
+
+// PushStack records a navigation in the directory stack of the session. If the directory where the
+// navigation starts is not the current one of the stack (e.g. the shell moved with "\cd"), it is pushed before.
+func PushStack(from, to string) error {
+	stack, err := utils.LoadStack()
+	if err != nil {
+		return err
+	}
+
+	if from != "" {
+		stack.Push(from)
+	}
+	stack.Push(to)
+
+	return utils.UpdateStack(stack)
+}
+
+// MoveStack moves the current directory of the stack n positions (negative is back) and returns it.
+// If save is false, the stack is not changed (only the directory is returned).
+func MoveStack(n int, save bool) (string, error) {
+	return moveStack(save, func(stack *gpath.DirStack) (string, error) {
+		return stack.Move(n)
+	})
+}
+
+// JumpStack makes the directory at the index of the stack the current directory and returns it.
+// If save is false, the stack is not changed (only the directory is returned).
+func JumpStack(index int, save bool) (string, error) {
+	return moveStack(save, func(stack *gpath.DirStack) (string, error) {
+		return stack.Jump(index)
+	})
+}
+
+// GetStack returns the directory stack of the session
+func GetStack() (gpath.DirStack, error) {
+	return utils.LoadStack()
+}
+
+// moveStack applies the move to the directory stack of the session, with the working directory pushed
+// before (to come back to it). The stack is only saved if the directory of the move exists.
+func moveStack(save bool, move func(stack *gpath.DirStack) (string, error)) (string, error) {
+	stack, err := utils.LoadStack()
+	if err != nil {
+		return "", err
+	}
+
+	if wd, err := os.Getwd(); err == nil && len(stack.Entries) > 0 {
+		stack.Push(wd)
+	}
+
+	path, err := move(&stack)
+	if err != nil {
+		return "", err
+	}
+
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("the directory \"%s\" of the stack doesn't exist anymore", path)
+	}
+
+	if !save {
+		return path, nil
+	}
+	return path, utils.UpdateStack(stack)
+}
@@ -0,0 +1,225 @@
+package core
+
+import (
+	"fmt"
+	"goto/src/utils"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Kinds of the actions of the uninstall
+const (
+	// Lines removed from a rc file
+	UninstallEdit = "edit"
+
+	// A file or directory removed
+	UninstallRemove = "remove"
+
+	// The goto-paths exported to a file
+	UninstallExport = "export"
+)
+
+// UninstallAction is a change done (or that would be done) by the uninstall
+type UninstallAction struct {
+	Kind string
+
+	// The file changed, removed or created
+	File string
+
+	// The lines removed from the rc file (only for UninstallEdit)
+	Lines []string
+}
+
+// UninstallOptions are the options of the uninstall
+type UninstallOptions struct {
+	// Remove the whole config directory (goto-paths, profiles, backups, history...) and the temporal gpaths
+	Purge bool
+
+	// Export the goto-paths to this file before removing anything (empty to don't export)
+	Export string
+
+	// Only return the actions, without changing anything
+	DryRun bool
+}
+
+// Uninstall reverses what init did: removes the block added to the rc files, the shell scripts, the
+// completion scripts, goto.bin and the copies of the rc files made by init. The goto-paths are kept,
+// unless the purge option is passed. If the export fails, nothing is changed.
+func Uninstall(opts UninstallOptions) ([]UninstallAction, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	configDir := utils.GetConfigDir()
+	actions := []UninstallAction{}
+
+	// The scripts that init could have written and sourced from the rc files
+	scriptFiles := getScriptFiles()
+	rcFiles := []string{}
+	for _, name := range ShellNames() {
+		if file := filepath.Join(homeDir, shellIntegrations[name].RCFile); !slices.Contains(rcFiles, file) {
+			rcFiles = append(rcFiles, file)
+		}
+	}
+	sort.Strings(rcFiles)
+
+	// The goto-paths are exported before changing anything, so a failed export doesn't leave the
+	// shell integration removed without the export
+	if opts.Export != "" {
+		if !opts.DryRun {
+			if err := BackupGPaths(opts.Export, false); err != nil {
+				return actions, fmt.Errorf("can't export the goto-paths, nothing was uninstalled: %v", err)
+			}
+		}
+		actions = append(actions, UninstallAction{Kind: UninstallExport, File: opts.Export})
+	}
+
+	for _, rcFile := range rcFiles {
+		content, err := os.ReadFile(rcFile)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return actions, err
+		}
+
+		cleaned, removed := removeInitLines(string(content), scriptFiles)
+		if len(removed) == 0 {
+			continue
+		}
+
+		if !opts.DryRun {
+			info, err := os.Stat(rcFile)
+			if err != nil {
+				return actions, err
+			}
+			if err := os.WriteFile(rcFile, []byte(cleaned), info.Mode().Perm()); err != nil {
+				return actions, err
+			}
+		}
+		actions = append(actions, UninstallAction{Kind: UninstallEdit, File: rcFile, Lines: removed})
+	}
+
+	files := append(slices.Clone(scriptFiles), getCompletionDir(), filepath.Join(configDir, "goto.bin"))
+
+	// The copies of the rc files made by init before changing them (see configureShell)
+	for _, rcFile := range rcFiles {
+		files = append(files, rcBackupPath(rcFile))
+	}
+
+	// The fish completion is only removed if it was generated by goto
+	fishCompletion := filepath.Join(homeDir, ".config", "fish", "completions", "goto.fish")
+	if content, err := os.ReadFile(fishCompletion); err == nil && strings.Contains(string(content), "__goto_") {
+		files = append(files, fishCompletion)
+	}
+
+	if opts.Purge {
+		files = append(files, configDir, filepath.Dir(utils.GetFilePath(true)))
+	}
+
+	for _, file := range files {
+		if _, err := os.Lstat(file); err != nil {
+			continue
+		}
+
+		if !opts.DryRun {
+			if err := os.RemoveAll(file); err != nil {
+				return actions, err
+			}
+		}
+		actions = append(actions, UninstallAction{Kind: UninstallRemove, File: file})
+	}
+
+	return actions, nil
+}
+
+// removeInitLines removes from the content of a rc file the block added by init and the source
+// commands of the scripts written by older versions. It returns the content and the removed lines.
+func removeInitLines(content string, scriptFiles []string) (string, []string) {
+	lines := strings.Split(content, "\n")
+	kept := []string{}
+	removed := []string{}
+
+	// popKept removes the last kept line if it is the line
+	popKept := func(line string) bool {
+		if len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == line {
+			kept = kept[:len(kept)-1]
+			return true
+		}
+		return false
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		switch {
+		case line == initBlockStart:
+			// The blank line written by init before the block
+			if popKept("") {
+				removed = append(removed, "")
+			}
+
+			// Remove until the end of the block (if the end is missing, only the start is removed)
+			end := max(findInitBlockEnd(lines, i), i)
+			removed = append(removed, lines[i:end+1]...)
+			i = end
+
+		case isSourceCommand(line, lastLine(kept), scriptFiles):
+			// The comment and the blank line written by the older versions of init
+			block := []string{lines[i]}
+			if popKept(initSourceComment) {
+				block = append([]string{initSourceComment}, block...)
+				if popKept("") {
+					block = append([]string{""}, block...)
+				}
+			}
+			removed = append(removed, block...)
+
+		default:
+			kept = append(kept, lines[i])
+		}
+	}
+
+	return strings.Join(kept, "\n"), removed
+}
+
+// isSourceCommand checks if the line loads one of the script files or, after the comment written by the
+// older versions of init (the previous line), a script of goto in any directory (e.g. an old config directory)
+func isSourceCommand(line, previous string, scriptFiles []string) bool {
+	for _, cmd := range []string{"source", "."} {
+		file, ok := strings.CutPrefix(line, cmd+" ")
+		if !ok {
+			continue
+		}
+
+		file = strings.Trim(strings.TrimSpace(file), "\"")
+		if slices.Contains(scriptFiles, file) {
+			return true
+		}
+		if strings.TrimSpace(previous) != initSourceComment {
+			continue
+		}
+		for _, scriptFile := range scriptFiles {
+			if filepath.Base(file) == filepath.Base(scriptFile) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String describes the action
+func (a UninstallAction) String() string {
+	switch a.Kind {
+	case UninstallEdit:
+		return fmt.Sprintf("remove %d lines from %s", len(a.Lines), a.File)
+	case UninstallExport:
+		return fmt.Sprintf("export the goto-paths to %s", a.File)
+	default:
+		return fmt.Sprintf("remove %s", a.File)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"goto/src/gpath"
 	"goto/src/utils"
+	"slices"
 	"strconv"
+	"strings"
 )
 
 const msgPathNotExist = "the Path \"%v\" doesn't exist in the goto-paths file"
@@ -12,10 +14,17 @@ const msgAbbvNotExist = "the Abbreviation \"%v\" doesn't exist in the goto-paths
 
 // UpdatePath updates a path based on the mode and new value.
 func UpdatePath(mode string, pathArg, abbvArg string, indexArg int, newValue string, useTemporal bool) error {
+	unlock, err := utils.LockGPaths(useTemporal)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	gpaths, err := utils.LoadGPaths(useTemporal)
 	if err != nil {
 		return err
 	}
+	before := slices.Clone(gpaths)
 
 	changeIndex := func(inx1, inx2 int) {
 		gpaths[inx1], gpaths[inx2] = gpaths[inx2], gpaths[inx1]
@@ -31,19 +40,25 @@ func UpdatePath(mode string, pathArg, abbvArg string, indexArg int, newValue str
 		{"indx-path", "ip"}, // 6
 		{"indx-abbv", "ia"}, // 7
 		{"indx-indx", "ii"}, // 8
+		{"path-tags", "pt"}, // 9
+		{"abbv-tags", "at"}, // 10
+		{"indx-tags", "it"}, // 11
+		{"path-desc", "pd"}, // 12
+		{"abbv-desc", "ad"}, // 13
+		{"indx-desc", "id"}, // 14
 	}
 
 	switch mode {
 
 	//path-path
 	case modes[0][0], modes[0][1]:
-		path, err := gpath.ValidPath(pathArg)
+		path, err := gpath.ValidPath(gpath.ExpandPath(pathArg))
 		if err != nil { return err }
 
-		if err := gpath.ValidPathVar(&newValue); err != nil { return err }
+		if err := gpath.ValidStoredPathVar(&newValue); err != nil { return err }
 
 		for i := range gpaths {
-			if gpaths[i].Path == path {
+			if gpaths[i].MatchesPath(path) {
 				gpaths[i].Path = newValue
 				break
 			}
@@ -54,13 +69,13 @@ func UpdatePath(mode string, pathArg, abbvArg string, indexArg int, newValue str
 
 	//path-abbv
 	case modes[1][0], modes[1][1]:
-		path, err := gpath.ValidPath(pathArg)
+		path, err := gpath.ValidPath(gpath.ExpandPath(pathArg))
 		if err != nil { return err }
 
-		if err := gpath.ValidAbbreviationVar(&newValue); err != nil { return err }
+		if err := gpath.ValidNewAbbreviationVar(&newValue); err != nil { return err }
 
 		for i := range gpaths {
-			if gpaths[i].Path == path {
+			if gpaths[i].MatchesPath(path) {
 				gpaths[i].Abbreviation = newValue
 				break
 			}
@@ -71,14 +86,14 @@ func UpdatePath(mode string, pathArg, abbvArg string, indexArg int, newValue str
 
 	//path-indx
 	case modes[2][0], modes[2][1]:
-		path, err := gpath.ValidPath(pathArg)
+		path, err := gpath.ValidPath(gpath.ExpandPath(pathArg))
 		if err != nil { return err }
 
 		if err := gpath.IsValidIndex(len(gpaths), newValue); err != nil { return err }
 		n, _ := strconv.Atoi(newValue)
 
 		for i := range gpaths {
-			if gpaths[i].Path == path {
+			if gpaths[i].MatchesPath(path) {
 				changeIndex(i, n)
 				break
 			}
@@ -92,7 +107,7 @@ func UpdatePath(mode string, pathArg, abbvArg string, indexArg int, newValue str
 		abbv, err := gpath.ValidAbbreviation(abbvArg)
 		if err != nil { return err }
 
-		if err := gpath.ValidPathVar(&newValue); err != nil { return err }
+		if err := gpath.ValidStoredPathVar(&newValue); err != nil { return err }
 
 		for i := range gpaths {
 			if gpaths[i].Abbreviation == abbv {
@@ -109,7 +124,7 @@ func UpdatePath(mode string, pathArg, abbvArg string, indexArg int, newValue str
 		abbv, err := gpath.ValidAbbreviation(abbvArg)
 		if err != nil { return err }
 
-		if err := gpath.ValidAbbreviationVar(&newValue); err != nil { return err }
+		if err := gpath.ValidNewAbbreviationVar(&newValue); err != nil { return err }
 
 		for i := range gpaths {
 			if gpaths[i].Abbreviation == abbv {
@@ -144,7 +159,7 @@ func UpdatePath(mode string, pathArg, abbvArg string, indexArg int, newValue str
 		indx := indexArg
 		if err := gpath.IsValidIndex(len(gpaths), strconv.Itoa(indx)); err != nil { return err }
 
-		if err := gpath.ValidPathVar(&newValue); err != nil { return err }
+		if err := gpath.ValidStoredPathVar(&newValue); err != nil { return err }
 
 		gpaths[indx].Path = newValue
 
@@ -153,7 +168,7 @@ func UpdatePath(mode string, pathArg, abbvArg string, indexArg int, newValue str
 		indx := indexArg
 		if err := gpath.IsValidIndex(len(gpaths), strconv.Itoa(indx)); err != nil { return err }
 
-		if err := gpath.ValidAbbreviationVar(&newValue); err != nil { return err }
+		if err := gpath.ValidNewAbbreviationVar(&newValue); err != nil { return err }
 
 		gpaths[indx].Abbreviation = newValue
 
@@ -167,9 +182,74 @@ func UpdatePath(mode string, pathArg, abbvArg string, indexArg int, newValue str
 
 		changeIndex(indx, n)
 
+	//path-tags, abbv-tags, indx-tags
+	case modes[9][0], modes[9][1], modes[10][0], modes[10][1], modes[11][0], modes[11][1]:
+		indx, err := findUpdateIndex(gpaths, mode, pathArg, abbvArg, indexArg)
+		if err != nil { return err }
+
+		// The tags are separated by commas, an empty value removes all tags
+		tags := []string{}
+		if strings.TrimSpace(newValue) != "" {
+			tags = strings.Split(newValue, ",")
+		}
+
+		if err := gpath.ValidTagsVar(&tags); err != nil { return err }
+
+		gpaths[indx].Tags = tags
+
+	//path-desc, abbv-desc, indx-desc
+	case modes[12][0], modes[12][1], modes[13][0], modes[13][1], modes[14][0], modes[14][1]:
+		indx, err := findUpdateIndex(gpaths, mode, pathArg, abbvArg, indexArg)
+		if err != nil { return err }
+
+		gpaths[indx].Description = strings.TrimSpace(newValue)
+
 	default:
 		return fmt.Errorf("invalid values of modes to update, use goto --modes")
 	}
 
-	return utils.UpdateGPaths(useTemporal, gpaths)
+	return saveChange(describeUpdate(mode, pathArg, abbvArg, indexArg, newValue), before, gpaths, useTemporal)
+}
+
+// describeUpdate returns the description of an update for the journal
+func describeUpdate(mode string, pathArg, abbvArg string, indexArg int, newValue string) string {
+	target := strconv.Itoa(indexArg)
+	if pathArg != "" {
+		target = pathArg
+	} else if abbvArg != "" {
+		target = abbvArg
+	}
+	return fmt.Sprintf("update-path %s %s => %s", mode, target, newValue)
+}
+
+// findUpdateIndex returns the index of the gpath identified by the first part of the mode
+// (path, abbv or indx, or its short form p, a or i)
+func findUpdateIndex(gpaths []gpath.GotoPath, mode string, pathArg, abbvArg string, indexArg int) (int, error) {
+	switch {
+	case strings.HasPrefix(mode, "p"):
+		path, err := gpath.ValidPath(gpath.ExpandPath(pathArg))
+		if err != nil { return -1, err }
+
+		for i := range gpaths {
+			if gpaths[i].MatchesPath(path) {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf(msgPathNotExist, path)
+
+	case strings.HasPrefix(mode, "a"):
+		abbv, err := gpath.ValidAbbreviation(abbvArg)
+		if err != nil { return -1, err }
+
+		for i := range gpaths {
+			if gpaths[i].Abbreviation == abbv {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf(msgAbbvNotExist, abbv)
+
+	default:
+		if err := gpath.IsValidIndex(len(gpaths), strconv.Itoa(indexArg)); err != nil { return -1, err }
+		return indexArg, nil
+	}
 }
@@ -1,8 +1,11 @@
 package core
 
 import (
+	"fmt"
 	"goto/src/gpath"
 	"goto/src/utils"
+	"os"
+	"slices"
 )
 
 // ValidatePaths checks if all paths in the config file are valid.
@@ -20,3 +23,118 @@ func ValidatePaths(useTemporal bool) error {
 
 	return gpath.CheckRepeatedItems(gpaths)
 }
+
+// Modes of FixPaths
+const (
+	// Delete the gpaths whose path can't be used (missing, not a directory, no permission or repeated)
+	FixDelete = "delete"
+
+	// Search the missing paths by their base name under the roots
+	FixRelocate = "relocate"
+
+	// Move the missing paths in the home directory of other user or machine to the home directory,
+	// stored relative to the home ("~/...") so they don't break again with the next change of the home
+	FixHomeRelative = "home-relative"
+)
+
+// FixModes are the valid modes of FixPaths
+var FixModes = []string{FixDelete, FixRelocate, FixHomeRelative}
+
+// Fix is a problem fixed by FixPaths
+type Fix struct {
+	Problem gpath.Problem
+	Action  string
+}
+
+// CheckPaths returns the gpaths of the config file and all their problems
+// (missing paths, repeated paths or abbreviations, invalid abbreviations...).
+func CheckPaths(useTemporal bool) ([]gpath.GotoPath, []gpath.Problem, error) {
+	gpaths, err := utils.ReadGPaths(useTemporal)
+	if err != nil {
+		return nil, nil, err
+	}
+	return gpaths, gpath.CheckGPaths(gpaths), nil
+}
+
+// FixPaths fixes the problems of the gpaths with the mode and returns the problems fixed and the problems
+// that remain. The roots are the directories where the missing paths are searched in the relocate mode.
+func FixPaths(mode string, roots []string, useTemporal bool) ([]Fix, []gpath.Problem, error) {
+	if !slices.Contains(FixModes, mode) {
+		return nil, nil, fmt.Errorf("invalid fix mode \"%s\" (valid modes: %v)", mode, FixModes)
+	}
+
+	unlock, err := utils.LockGPaths(useTemporal)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	gpaths, err := utils.ReadGPaths(useTemporal)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	home, _ := os.UserHomeDir()
+	fixed := slices.Clone(gpaths)
+	deleted := map[int]bool{}
+	fixes := []Fix{}
+
+	// A new path can't be the path of other gpath
+	used := func(path string) bool {
+		return slices.ContainsFunc(fixed, func(gp gpath.GotoPath) bool { return gp.MatchesPath(path) })
+	}
+
+	for _, p := range gpath.CheckGPaths(gpaths) {
+		switch {
+		// The file can't be saved with repeated items, they are fixed with any mode
+		case p.Kind == gpath.ProblemDuplicatePath:
+			deleted[p.Index] = true
+			fixes = append(fixes, Fix{Problem: p, Action: "deleted"})
+
+		case p.Kind == gpath.ProblemDuplicateAbbv:
+			abbv, err := uniqueAbbreviation(p.GotoPath.ExpandedPath(), usedAbbreviations(fixed))
+			if err != nil {
+				return nil, nil, err
+			}
+			fixed[p.Index].Abbreviation = abbv
+			fixes = append(fixes, Fix{Problem: p, Action: "renamed to \"" + abbv + "\""})
+
+		case mode == FixDelete && p.IsDeadPath():
+			if !deleted[p.Index] {
+				deleted[p.Index] = true
+				fixes = append(fixes, Fix{Problem: p, Action: "deleted"})
+			}
+
+		case mode == FixRelocate && p.Kind == gpath.ProblemMissing:
+			candidates := slices.DeleteFunc(gpath.RelocatePath(p.GotoPath.ExpandedPath(), roots), used)
+			if len(candidates) == 1 {
+				fixed[p.Index].Path = candidates[0]
+				fixes = append(fixes, Fix{Problem: p, Action: "relocated to \"" + candidates[0] + "\""})
+			}
+
+		case mode == FixHomeRelative && p.Kind == gpath.ProblemMissing:
+			if path, ok := gpath.RebaseOnHome(p.GotoPath.ExpandedPath(), home); ok && !used(path) {
+				if info, err := os.Stat(path); err == nil && info.IsDir() {
+					portable := gpath.PortablePath(path, nil)
+					fixed[p.Index].Path = portable
+					fixes = append(fixes, Fix{Problem: p, Action: "moved to \"" + portable + "\""})
+				}
+			}
+		}
+	}
+
+	after := []gpath.GotoPath{}
+	for i, gp := range fixed {
+		if !deleted[i] {
+			after = append(after, gp)
+		}
+	}
+
+	if len(fixes) > 0 {
+		if err := saveChange("valid-paths --fix "+mode, gpaths, after, useTemporal); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return fixes, gpath.CheckGPaths(after), nil
+}
@@ -0,0 +1,158 @@
+package gpath
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+)
+
+// Kinds of the problems of a gpath
+const (
+	ProblemMissing             = "missing"
+	ProblemNotDirectory        = "not-a-directory"
+	ProblemPermissionDenied    = "permission-denied"
+	ProblemSymlinkLoop         = "symlink-loop"
+	ProblemInvalidPath         = "invalid-path"
+	ProblemDuplicatePath       = "duplicate-path"
+	ProblemDuplicateAbbv       = "duplicate-abbreviation"
+	ProblemInvalidAbbreviation = "invalid-abbreviation"
+	ProblemInvalidTags         = "invalid-tags"
+)
+
+// Max depth of the directories searched under a root to relocate a path
+const RelocateMaxDepth = 4
+
+// Home directories of other users or machines (/home/user, /Users/user or C:\Users\user)
+var homeDirRegexp = regexp.MustCompile(`^(/home/[^/]+|/Users/[^/]+|[A-Za-z]:\\Users\\[^\\]+)(.*)$`)
+
+// Problem is a problem of a gpath of the goto-paths file
+type Problem struct {
+	Index    int
+	GotoPath GotoPath
+	Kind     string
+	Message  string
+}
+
+// IsDeadPath reports if the problem is that the path can't be used (it doesn't exist, it is not
+// a directory, it can't be accessed or it is repeated)
+func (p Problem) IsDeadPath() bool {
+	switch p.Kind {
+	case ProblemMissing, ProblemNotDirectory, ProblemPermissionDenied, ProblemSymlinkLoop, ProblemInvalidPath, ProblemDuplicatePath:
+		return true
+	}
+	return false
+}
+
+// CheckGPaths returns all the problems of the gpaths (in the order of the gpaths)
+func CheckGPaths(gpaths []GotoPath) []Problem {
+	problems := []Problem{}
+	add := func(i int, kind, format string, args ...interface{}) {
+		problems = append(problems, Problem{Index: i, GotoPath: gpaths[i], Kind: kind, Message: fmt.Sprintf(format, args...)})
+	}
+
+	paths := map[string]int{}
+	abbvs := map[string]int{}
+	for i, gp := range gpaths {
+		// The portable paths are checked with their expansion
+		path := gp.ExpandedPath()
+		if first, exists := paths[path]; exists {
+			add(i, ProblemDuplicatePath, "the path \"%s\" is repeated (index %v)", gp.Path, first)
+		} else if kind, err := checkPath(path); err != nil {
+			add(i, kind, "%v", err)
+		}
+		paths[path] = i
+
+		if _, err := ValidAbbreviation(gp.Abbreviation); err != nil {
+			add(i, ProblemInvalidAbbreviation, "%v", err)
+		} else if first, exists := abbvs[gp.Abbreviation]; exists {
+			add(i, ProblemDuplicateAbbv, "the abbreviation \"%s\" is repeated (index %v)", gp.Abbreviation, first)
+		}
+		abbvs[gp.Abbreviation] = i
+
+		if _, err := ValidTags(gp.Tags); err != nil {
+			add(i, ProblemInvalidTags, "%v", err)
+		}
+	}
+
+	return problems
+}
+
+// checkPath returns the kind of the problem of the path and the error (nil if it is a valid directory)
+func checkPath(path string) (string, error) {
+	if strings.TrimSpace(path) == "" || !filepath.IsAbs(path) {
+		return ProblemInvalidPath, fmt.Errorf("the path \"%s\" is not an absolute path", path)
+	}
+
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, syscall.ELOOP):
+		return ProblemSymlinkLoop, fmt.Errorf("the path \"%s\" has a symlink loop", path)
+	case errors.Is(err, fs.ErrNotExist):
+		return ProblemMissing, fmt.Errorf("the path \"%s\" doesn't exist", path)
+	case errors.Is(err, fs.ErrPermission):
+		return ProblemPermissionDenied, fmt.Errorf("permission denied to access \"%s\"", path)
+	case err != nil:
+		return ProblemInvalidPath, fmt.Errorf("error to get info of \"%s\": %v", path, err)
+	case !info.IsDir():
+		return ProblemNotDirectory, fmt.Errorf("the path \"%s\" is not a directory", path)
+	}
+
+	// The directory exists, check that it can be opened
+	dir, err := os.Open(path)
+	if errors.Is(err, fs.ErrPermission) {
+		return ProblemPermissionDenied, fmt.Errorf("permission denied to access \"%s\"", path)
+	}
+	if err == nil {
+		dir.Close()
+	}
+
+	return "", nil
+}
+
+// RelocatePath searches under the roots the directories with the same base name that the path
+// (up to RelocateMaxDepth levels, hidden directories are skipped) and returns the candidates.
+func RelocatePath(path string, roots []string) []string {
+	base := filepath.Base(path)
+	candidates := []string{}
+	seen := map[string]bool{}
+
+	for _, root := range roots {
+		root = filepath.Clean(root)
+		rootDepth := strings.Count(root, string(filepath.Separator))
+
+		_ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return nil
+			}
+			if p != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if p != root && d.Name() == base && !seen[p] {
+				seen[p] = true
+				candidates = append(candidates, p)
+			}
+			if strings.Count(p, string(filepath.Separator))-rootDepth >= RelocateMaxDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+	}
+
+	return candidates
+}
+
+// RebaseOnHome returns the path in the home directory when the path is in the home directory
+// of other user or machine (e.g. "/home/old/projects" => "/home/me/projects")
+func RebaseOnHome(path, home string) (string, bool) {
+	match := homeDirRegexp.FindStringSubmatch(path)
+	if match == nil || match[1] == home {
+		return "", false
+	}
+	rest := strings.TrimLeft(match[2], `/\`)
+	return filepath.Join(home, filepath.FromSlash(rest)), true
+}
@@ -0,0 +1,59 @@
+package gpath
+
+import "slices"
+
+// Change is a gpath whose path is in both arrays but with other abbreviation, tags or description
+type Change struct {
+	Before GotoPath
+	After  GotoPath
+}
+
+// Diff are the differences between two arrays of gpaths, keyed by the path
+type Diff struct {
+	Added   []GotoPath
+	Removed []GotoPath
+	Changed []Change
+}
+
+// Empty reports if the arrays have the same gpaths
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Equal reports if both gpaths have the same path, abbreviation, tags and description
+func (g GotoPath) Equal(other GotoPath) bool {
+	return g.Path == other.Path && g.Abbreviation == other.Abbreviation &&
+		slices.Equal(g.Tags, other.Tags) && g.Description == other.Description
+}
+
+// DiffGPaths returns the gpaths added, removed and changed from before to after (keyed by the path).
+// The gpaths are in the order of after (added and changed) and before (removed).
+func DiffGPaths(before, after []GotoPath) Diff {
+	diff := Diff{Added: []GotoPath{}, Removed: []GotoPath{}, Changed: []Change{}}
+
+	beforeByPath := make(map[string]GotoPath, len(before))
+	for _, gp := range before {
+		beforeByPath[gp.Path] = gp
+	}
+
+	afterPaths := make(map[string]bool, len(after))
+	for _, gp := range after {
+		afterPaths[gp.Path] = true
+
+		old, exists := beforeByPath[gp.Path]
+		switch {
+		case !exists:
+			diff.Added = append(diff.Added, gp)
+		case !old.Equal(gp):
+			diff.Changed = append(diff.Changed, Change{Before: old, After: gp})
+		}
+	}
+
+	for _, gp := range before {
+		if !afterPaths[gp.Path] {
+			diff.Removed = append(diff.Removed, gp)
+		}
+	}
+
+	return diff
+}
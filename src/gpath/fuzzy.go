@@ -0,0 +1,134 @@
+package gpath
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Scores used to rank the matches, an exact hit is better than a prefix,
+// a prefix better than a substring and a substring better than a subsequence
+const (
+	scoreExact       = 100
+	scorePrefix      = 75
+	scoreSubstring   = 50
+	scoreSubsequence = 10
+)
+
+// Match is a GotoPath that matches the terms of a fuzzy search
+type Match struct {
+	Index    int
+	GotoPath GotoPath
+	Score    int
+}
+
+// FuzzyMatch returns the gpaths that match the terms, ranked from the best to the worst match.
+//
+// With one term, the Abbreviation and the base name of the Path are matched (exact, prefix,
+// substring or subsequence). With one or more terms, each term must also match a different
+// segment of the Path in order, and the last term must match the base name
+// (e.g. "proj api" matches "/home/me/projects/billing-api").
+func FuzzyMatch(gpaths []GotoPath, terms []string) []Match {
+	cleanTerms := make([]string, 0, len(terms))
+	for _, t := range terms {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			cleanTerms = append(cleanTerms, t)
+		}
+	}
+
+	if len(cleanTerms) == 0 {
+		return nil
+	}
+
+	var matches []Match
+	for i, gp := range gpaths {
+		score := segmentsScore(gp.ExpandedPath(), cleanTerms)
+
+		if len(cleanTerms) == 1 {
+			score = max(score, termScore(gp.Abbreviation, cleanTerms[0]), termScore(filepath.Base(gp.ExpandedPath()), cleanTerms[0]))
+		}
+
+		if score > 0 {
+			matches = append(matches, Match{Index: i, GotoPath: gp, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	return matches
+}
+
+// IsAmbiguous reports if the best match can't be distinguished from the second one
+func IsAmbiguous(matches []Match) bool {
+	return len(matches) > 1 && matches[0].Score == matches[1].Score
+}
+
+// Return the score of the term matched against the value (0 if doesn't match)
+func termScore(value, term string) int {
+	value = strings.ToLower(value)
+
+	switch {
+	case value == term:
+		return scoreExact
+	case strings.HasPrefix(value, term):
+		return scorePrefix
+	case strings.Contains(value, term):
+		return scoreSubstring
+	case isSubsequence(value, term):
+		return scoreSubsequence
+	default:
+		return 0
+	}
+}
+
+// Return the score of the terms matched in order against the segments of the path (0 if doesn't match).
+// The last term must match the last segment of the path.
+func segmentsScore(path string, terms []string) int {
+	segments := strings.FieldsFunc(filepath.ToSlash(path), func(r rune) bool { return r == '/' })
+	if len(segments) < len(terms) {
+		return 0
+	}
+
+	// The last term is matched against the base name
+	score := termScore(segments[len(segments)-1], terms[len(terms)-1])
+	if score == 0 {
+		return 0
+	}
+
+	// The other terms are matched (in order) against the rest of the segments
+	seg := 0
+	for _, term := range terms[:len(terms)-1] {
+		found := false
+		for ; seg < len(segments)-1; seg++ {
+			if s := termScore(segments[seg], term); s > 0 {
+				score += s
+				found = true
+				seg++
+				break
+			}
+		}
+		if !found {
+			return 0
+		}
+	}
+
+	// A segment match is never better than a direct match of a single term
+	return score / len(terms) / 2
+}
+
+// Check if all the characters of sub appear in s in the same order
+func isSubsequence(s, sub string) bool {
+	subRunes := []rune(sub)
+	i := 0
+	for _, r := range s {
+		if i == len(subRunes) {
+			break
+		}
+		if r == subRunes[i] {
+			i++
+		}
+	}
+	return i == len(subRunes)
+}
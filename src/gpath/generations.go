@@ -0,0 +1,105 @@
+package gpath
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Layout of the timestamp in the names of the generations (sortable)
+const generationTimeLayout = "20060102T150405.000000000Z"
+
+// Generation is a snapshot of a goto-paths file, taken before a change
+type Generation struct {
+	File string
+	Time time.Time
+}
+
+// SnapshotFile copies the file to the directory as a new generation named "<prefix>-<timestamp>.json" and
+// keeps only the newest keep generations of the prefix. If the file doesn't exist, it can't be parsed (a
+// generation must be restorable) or it is the same as the newest generation, nothing is copied.
+func SnapshotFile(file, dir, prefix string, keep int, now time.Time) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, _, err := DecodeGPaths(data); err != nil {
+		return nil
+	}
+
+	generations, err := ListGenerations(dir, prefix)
+	if err != nil {
+		return err
+	}
+
+	if len(generations) == 0 || !sameContent(generations[0].File, data) {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+
+		name := fmt.Sprintf("%s-%s.json", prefix, now.UTC().Format(generationTimeLayout))
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			return err
+		}
+
+		generations = append([]Generation{{File: filepath.Join(dir, name)}}, generations...)
+	}
+
+	// Remove the oldest generations
+	for _, g := range generations[min(keep, len(generations)):] {
+		if err := os.Remove(g.File); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListGenerations returns the generations of the prefix in the directory, the newest first
+func ListGenerations(dir, prefix string) ([]Generation, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []Generation{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	generations := []Generation{}
+	for _, e := range entries {
+		stamp, ok := strings.CutPrefix(e.Name(), prefix+"-")
+		if !ok || e.IsDir() {
+			continue
+		}
+
+		t, err := time.Parse(generationTimeLayout, strings.TrimSuffix(stamp, ".json"))
+		if err != nil {
+			continue
+		}
+
+		generations = append(generations, Generation{File: filepath.Join(dir, e.Name()), Time: t})
+	}
+
+	sort.Slice(generations, func(i, j int) bool {
+		return generations[i].Time.After(generations[j].Time)
+	})
+
+	return generations, nil
+}
+
+// Check if the content of the file is data
+func sameContent(file string, data []byte) bool {
+	content, err := os.ReadFile(file)
+	return err == nil && bytes.Equal(content, data)
+}
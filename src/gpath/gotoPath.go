@@ -1,22 +1,63 @@
 package gpath
 
+import (
+	"strings"
+	"time"
+)
+
 //
 // GotoPath Type
 //
 type GotoPath struct {
-	Path         string `json:"path"`
-	Abbreviation string `json:"abbreviation"`
+	Path         string   `json:"path"`
+	Abbreviation string   `json:"abbreviation"`
+	Tags         []string `json:"tags,omitempty"`
+	Description  string   `json:"description,omitempty"`
+
+	// Only for the temporal gpaths: when the gpath expires (unix time, 0 if it doesn't expire)
+	// and the shell session that can see it (empty for all the sessions)
+	ExpiresAt int64  `json:"expires_at,omitempty"`
+	Session   string `json:"session,omitempty"`
+
+	// The file where the gpath was loaded from, only when it is not the goto-paths
+	// file in use (e.g. a project file or the file of a base profile)
+	Source string `json:"-"`
 }
 
 // Return gpath in String format
 func (d *GotoPath) String() string {
-	return "\"" + d.Path + "\" - " + d.Abbreviation
+	s := "\"" + d.Path + "\""
+
+	// A portable path is shown with its expansion
+	if expanded := d.ExpandedPath(); expanded != d.Path {
+		s += " (" + expanded + ")"
+	}
+
+	s += " - " + d.Abbreviation
+
+	if len(d.Tags) > 0 {
+		s += " [" + strings.Join(d.Tags, ", ") + "]"
+	}
+
+	if d.Description != "" {
+		s += " - " + d.Description
+	}
+
+	if d.ExpiresAt != 0 {
+		s += " (expires " + time.Unix(d.ExpiresAt, 0).Format("2006-01-02 15:04") + ")"
+	}
+
+	if d.Session != "" {
+		s += " (session " + d.Session + ")"
+	}
+
+	return s
 }
 
-// This function valid a directory with ValidPathVar() and ValidAbbreviationVar()
+// This function valid a directory with ValidPathVar() (of the expanded path), ValidAbbreviationVar() and ValidTagsVar()
 func (d GotoPath) Valid() error {
 
-	if _, err := ValidPath(d.Path); err != nil {
+	if _, err := ValidPath(d.ExpandedPath()); err != nil {
 		return err
 	}
 
@@ -24,5 +65,44 @@ func (d GotoPath) Valid() error {
 		return err
 	}
 
+	if _, err := ValidTags(d.Tags); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// HasTags checks if the gpath has all the tags (matchAll) or at least one of them (!matchAll).
+// The tags are compared case-insensitively.
+func (d GotoPath) HasTags(tags []string, matchAll bool) bool {
+	for _, tag := range tags {
+		found := false
+		for _, own := range d.Tags {
+			if strings.EqualFold(own, tag) {
+				found = true
+				break
+			}
+		}
+
+		if found && !matchAll {
+			return true
+		}
+
+		if !found && matchAll {
+			return false
+		}
+	}
+
+	return matchAll
+}
+
+// FilterByTags returns the indexes of the gpaths that have the tags (see HasTags)
+func FilterByTags(gpaths []GotoPath, tags []string, matchAll bool) []int {
+	indexes := []int{}
+	for i := range gpaths {
+		if gpaths[i].HasTags(tags, matchAll) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
@@ -0,0 +1,178 @@
+package gpath
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+const (
+	// When the sum of all the counts exceeds this value, all the counts are aged
+	HistoryMaxCount = 10000
+
+	// Factor used to age the counts of the history
+	HistoryAgingFactor = 0.9
+
+	// Entries that are not visited in this time are pruned from the history
+	HistoryMaxAge = 90 * 24 * time.Hour
+)
+
+// HistoryEntry is a visit record of the navigation history
+type HistoryEntry struct {
+	Path      string  `json:"path"`
+	Count     float64 `json:"count"`
+	LastVisit int64   `json:"last_visit"`
+}
+
+// Frecency returns the score of the entry: the count weighted by how recent was the last visit
+func (h HistoryEntry) Frecency(now time.Time) float64 {
+	elapsed := now.Sub(time.Unix(h.LastVisit, 0))
+
+	switch {
+	case elapsed < time.Hour:
+		return h.Count * 4
+	case elapsed < 24*time.Hour:
+		return h.Count * 2
+	case elapsed < 7*24*time.Hour:
+		return h.Count / 2
+	default:
+		return h.Count / 4
+	}
+}
+
+// RecordVisit adds a visit of the path to the history and returns the history updated.
+// The history is pruned after adding the visit (see PruneHistory)
+func RecordVisit(history []HistoryEntry, path string, now time.Time) []HistoryEntry {
+	found := false
+	for i := range history {
+		if history[i].Path == path {
+			history[i].Count++
+			history[i].LastVisit = now.Unix()
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		history = append(history, HistoryEntry{
+			Path:      path,
+			Count:     1,
+			LastVisit: now.Unix(),
+		})
+	}
+
+	return PruneHistory(history, now)
+}
+
+// PruneHistory ages and prunes the history:
+// - If the sum of the counts exceeds HistoryMaxCount, all counts are multiplied by HistoryAgingFactor
+// - Entries with a count lower than 1 are removed
+// - Entries not visited in HistoryMaxAge are removed
+func PruneHistory(history []HistoryEntry, now time.Time) []HistoryEntry {
+	var total float64
+	for _, h := range history {
+		total += h.Count
+	}
+
+	aging := total > HistoryMaxCount
+
+	pruned := history[:0]
+	for _, h := range history {
+		if aging {
+			h.Count *= HistoryAgingFactor
+		}
+
+		if h.Count < 1 || now.Sub(time.Unix(h.LastVisit, 0)) > HistoryMaxAge {
+			continue
+		}
+		pruned = append(pruned, h)
+	}
+
+	return pruned
+}
+
+// BestFrecencyMatch returns the path with the best frecency score that matches all the keywords.
+// The keywords must appear in order in the path (case-insensitive) and the last keyword must
+// match the last element of the path. Only existing directories are considered.
+func BestFrecencyMatch(history []HistoryEntry, keywords []string, now time.Time) (string, bool) {
+	matches := make([]HistoryEntry, 0, len(history))
+	for _, h := range history {
+		if matchKeywords(h.Path, keywords) {
+			matches = append(matches, h)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Frecency(now) > matches[j].Frecency(now)
+	})
+
+	for _, h := range matches {
+		if info, err := os.Stat(h.Path); err == nil && info.IsDir() {
+			return h.Path, true
+		}
+	}
+
+	return "", false
+}
+
+// Check that the keywords appear in order in the path and the last one in the base name
+func matchKeywords(path string, keywords []string) bool {
+	if len(keywords) == 0 {
+		return false
+	}
+
+	lowerPath := strings.ToLower(path)
+	pos := 0
+	for _, k := range keywords {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if k == "" {
+			return false
+		}
+
+		i := strings.Index(lowerPath[pos:], k)
+		if i == -1 {
+			return false
+		}
+		pos += i + len(k)
+	}
+
+	last := strings.ToLower(keywords[len(keywords)-1])
+	return strings.Contains(strings.ToLower(filepath.Base(path)), last)
+}
+
+// Save the history in the history file atomically (see writeFileAtomic)
+func SaveHistoryFile(history []HistoryEntry, historyFile string) error {
+	if err := os.MkdirAll(filepath.Dir(historyFile), 0755); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(historyFile, func(w io.Writer) error {
+		return sonic.ConfigDefault.NewEncoder(w).Encode(history)
+	})
+}
+
+// Load the history file into an array. If the file doesn't exist, the history is empty
+func LoadHistoryFile(history *[]HistoryEntry, historyFile string) error {
+	file, err := os.Open(historyFile)
+	if os.IsNotExist(err) {
+		*history = []HistoryEntry{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading history file")
+	}
+	defer file.Close()
+
+	if err := sonic.ConfigFastest.NewDecoder(bufio.NewReader(file)).Decode(history); err != nil {
+		return fmt.Errorf("error parsing history file")
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package gpath
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// Number of operations kept in a journal, the oldest are removed
+const JournalMaxEntries = 100
+
+// JournalEntry is an operation over a goto-paths file, with the gpaths before and after it
+type JournalEntry struct {
+	Operation string     `json:"operation"`
+	Before    []GotoPath `json:"before"`
+	After     []GotoPath `json:"after"`
+	Timestamp int64      `json:"timestamp"`
+
+	// The id shared with the entry of the other journal of the same operation (e.g. a promote changes
+	// the goto-paths file and the temporal file), both are undone and redone together
+	Link string `json:"link,omitempty"`
+}
+
+// Journal is the list of operations over a goto-paths file. The entries before the position are
+// applied (they can be undone) and the entries from the position are undone (they can be redone).
+type Journal struct {
+	Entries  []JournalEntry `json:"entries"`
+	Position int            `json:"position"`
+}
+
+// Record adds an operation to the journal, link is the id of its entry in the other journal ("" if it
+// only changes this file). The undone operations are discarded.
+func (j *Journal) Record(operation, link string, before, after []GotoPath, now time.Time) {
+	j.Entries = append(j.Entries[:j.Position], JournalEntry{
+		Operation: operation,
+		Before:    slices.Clone(before),
+		After:     slices.Clone(after),
+		Timestamp: now.Unix(),
+		Link:      link,
+	})
+
+	if len(j.Entries) > JournalMaxEntries {
+		j.Entries = j.Entries[len(j.Entries)-JournalMaxEntries:]
+	}
+	j.Position = len(j.Entries)
+}
+
+// Undo returns the entry of the last applied operation, the current gpaths must be the gpaths after it.
+// The position is moved back, so the operation can be redone.
+func (j *Journal) Undo(current []GotoPath) (JournalEntry, error) {
+	if j.Position == 0 {
+		return JournalEntry{}, fmt.Errorf("there is nothing to undo")
+	}
+
+	entry := j.Entries[j.Position-1]
+	if !DiffGPaths(entry.After, current).Empty() {
+		return JournalEntry{}, fmt.Errorf("can't undo %s: the goto-paths file was changed outside of goto", entry.Operation)
+	}
+
+	j.Position--
+	return entry, nil
+}
+
+// Redo returns the entry of the last undone operation, the current gpaths must be the gpaths before it.
+// The position is moved forward, so the operation can be undone again.
+func (j *Journal) Redo(current []GotoPath) (JournalEntry, error) {
+	if j.Position == len(j.Entries) {
+		return JournalEntry{}, fmt.Errorf("there is nothing to redo")
+	}
+
+	entry := j.Entries[j.Position]
+	if !DiffGPaths(entry.Before, current).Empty() {
+		return JournalEntry{}, fmt.Errorf("can't redo %s: the goto-paths file was changed outside of goto", entry.Operation)
+	}
+
+	j.Position++
+	return entry, nil
+}
+
+// Save the journal in the journal file
+func SaveJournalFile(journal Journal, journalFile string) error {
+	data, err := sonic.ConfigDefault.Marshal(journal)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(journalFile, data, 0600)
+}
+
+// Load the journal file. If the file doesn't exist, the journal is empty
+func LoadJournalFile(journalFile string) (Journal, error) {
+	journal := Journal{Entries: []JournalEntry{}}
+
+	file, err := os.Open(journalFile)
+	if os.IsNotExist(err) {
+		return journal, nil
+	}
+	if err != nil {
+		return journal, fmt.Errorf("error reading journal file")
+	}
+	defer file.Close()
+
+	if err := sonic.ConfigFastest.NewDecoder(bufio.NewReader(file)).Decode(&journal); err != nil {
+		return journal, fmt.Errorf("error parsing journal file")
+	}
+
+	if journal.Position < 0 || journal.Position > len(journal.Entries) {
+		journal.Position = len(journal.Entries)
+	}
+
+	return journal, nil
+}
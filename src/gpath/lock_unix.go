@@ -0,0 +1,27 @@
+//go:build !windows
+
+package gpath
+
+import (
+	"os"
+	"syscall"
+)
+
+// LockFile takes an exclusive advisory lock associated to the file (using the file "<file>.lock"),
+// blocking until the lock is available. The returned function releases the lock.
+func LockFile(file string) (func(), error) {
+	lockFile, err := os.OpenFile(file+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX); err != nil {
+		lockFile.Close()
+		return nil, err
+	}
+
+	return func() {
+		_ = syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN)
+		lockFile.Close()
+	}, nil
+}
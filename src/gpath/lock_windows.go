@@ -0,0 +1,8 @@
+//go:build windows
+
+package gpath
+
+// LockFile is a no-op on Windows, the advisory locks are not supported.
+func LockFile(file string) (func(), error) {
+	return func() {}, nil
+}
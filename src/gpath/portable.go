@@ -0,0 +1,134 @@
+package gpath
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// A portable path starts with "~" or with an environment variable ("$VAR" or "${VAR}")
+var portablePathRegexp = regexp.MustCompile(`^(~|\$[A-Za-z_][A-Za-z0-9_]*|\$\{[A-Za-z_][A-Za-z0-9_]*\})(/.*)?$`)
+
+// IsPortable checks if the path is a portable path: a path relative to the home ("~/...") or to an
+// environment variable ("$VAR/..." or "${VAR}/..."), that is expanded when it is resolved
+func IsPortable(path string) bool {
+	return portablePathRegexp.MatchString(filepath.ToSlash(strings.TrimSpace(path)))
+}
+
+// ExpandPath expands the home ("~") or the environment variable at the start of a portable path.
+// Other paths are returned without changes. If the variable is not set, the path is not expanded.
+func ExpandPath(path string) string {
+	expanded, err := ExpandPathStrict(path)
+	if err != nil {
+		return path
+	}
+	return expanded
+}
+
+// ExpandPathStrict is like ExpandPath, but it fails if the home or the environment variable
+// of a portable path can't be expanded (e.g. to don't navigate to "$WORK")
+func ExpandPathStrict(path string) (string, error) {
+	match := portablePathRegexp.FindStringSubmatch(filepath.ToSlash(strings.TrimSpace(path)))
+	if match == nil {
+		return path, nil
+	}
+
+	var base string
+	if match[1] == "~" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("can't expand the path \"%s\": %v", path, err)
+		}
+		base = home
+	} else {
+		name := strings.Trim(match[1], "${}")
+		value, ok := os.LookupEnv(name)
+		if !ok || value == "" {
+			return "", fmt.Errorf("environment variable %s is not set (used by the path \"%s\")", name, path)
+		}
+		base = value
+	}
+
+	return filepath.Clean(filepath.Join(base, filepath.FromSlash(match[2]))), nil
+}
+
+// ExpandedPath returns the path of the gpath with the home or the environment variable expanded
+func (d GotoPath) ExpandedPath() string {
+	return ExpandPath(d.Path)
+}
+
+// ResolvedPath returns the expanded path of the gpath, or an error if it can't be expanded (see ExpandPathStrict)
+func (d GotoPath) ResolvedPath() (string, error) {
+	return ExpandPathStrict(d.Path)
+}
+
+// MatchesPath checks if the gpath is the path (the stored one or the expanded one)
+func (d GotoPath) MatchesPath(path string) bool {
+	return d.Path == path || d.ExpandedPath() == path
+}
+
+// PortablePath returns the path relative to the first environment variable that contains it ("$VAR/...")
+// or, if none contains it, relative to the home ("~/..."). If the path is not inside any of them,
+// it is returned without changes. The path must be absolute and clean.
+func PortablePath(path string, vars []string) string {
+	for _, name := range vars {
+		if name == "HOME" {
+			continue
+		}
+
+		if rel, ok := relativeTo(path, os.Getenv(name)); ok {
+			return filepath.ToSlash(filepath.Join("$"+name, rel))
+		}
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		if rel, ok := relativeTo(path, home); ok {
+			return filepath.ToSlash(filepath.Join("~", rel))
+		}
+	}
+
+	return path
+}
+
+// relativeTo returns the path relative to the base if it is inside it
+func relativeTo(path, base string) (string, bool) {
+	if base == "" || !filepath.IsAbs(base) {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(filepath.Clean(base), path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// ValidStoredPathVar validates and cleans in-place a path to store in a gpath. A portable path
+// ("~/..." or "$VAR/...") is kept portable (cleaned) if its expansion is a valid path, other
+// paths are validated and converted to absolute paths with ValidPathVar.
+func ValidStoredPathVar(path *string) error {
+	if !IsPortable(*path) {
+		return ValidPathVar(path)
+	}
+
+	portable := filepath.ToSlash(filepath.Clean(strings.TrimSpace(*path)))
+	expanded, err := ExpandPathStrict(portable)
+	if err != nil {
+		return err
+	}
+
+	if err := ValidPathVar(&expanded); err != nil {
+		return err
+	}
+
+	*path = portable
+	return nil
+}
+
+// ValidStoredPath is a wrapper around ValidStoredPathVar for convenience.
+func ValidStoredPath(path string) (string, error) {
+	err := ValidStoredPathVar(&path)
+	return path, err
+}
@@ -0,0 +1,67 @@
+package gpath
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Load a project file (of any schema version) into an array. The relative paths of the file are resolved against the
+// directory of the file (the portable paths are kept) and the Source of each gpath is the file.
+func LoadProjectFile(projectFile string) ([]GotoPath, error) {
+	data, err := os.ReadFile(projectFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading project file \"%s\"", projectFile)
+	}
+
+	// The project files are committed, so they aren't migrated (an older goto can still read them)
+	gpaths, _, err := DecodeGPaths(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing project file \"%s\"", projectFile)
+	}
+
+	// An empty project file is valid
+	if len(gpaths) == 0 {
+		return []GotoPath{}, nil
+	}
+
+	dir := filepath.Dir(projectFile)
+	for i := range gpaths {
+		if !IsPortable(gpaths[i].Path) {
+			if !filepath.IsAbs(gpaths[i].Path) {
+				gpaths[i].Path = filepath.Join(dir, gpaths[i].Path)
+			}
+			gpaths[i].Path = filepath.Clean(gpaths[i].Path)
+		}
+		gpaths[i].Source = projectFile
+	}
+
+	if err := CheckRepeatedItems(gpaths); err != nil {
+		return nil, fmt.Errorf("error in project file \"%s\": %v", projectFile, err)
+	}
+
+	return gpaths, nil
+}
+
+// Save the array in a project file atomically (see writeFileAtomic), the paths are saved relative to
+// the directory of the file so the file can be committed to the repository.
+func SaveProjectFile(gpaths []GotoPath, projectFile string) error {
+	if err := CheckRepeatedItems(gpaths); err != nil {
+		return err
+	}
+
+	if err := CheckWritableFile(projectFile); err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(projectFile)
+	relGPaths := make([]GotoPath, len(gpaths))
+	for i, gp := range gpaths {
+		if rel, err := filepath.Rel(dir, gp.Path); err == nil && filepath.IsAbs(gp.Path) {
+			gp.Path = rel
+		}
+		relGPaths[i] = gp
+	}
+
+	return writeGPathsFile(relGPaths, projectFile)
+}
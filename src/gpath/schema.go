@@ -0,0 +1,183 @@
+package gpath
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+const (
+	// The version of the layout of the goto-paths files written by this version of goto
+	SchemaVersion = 1
+
+	// The version of the legacy goto-paths files: a bare array of gpaths, without envelope
+	LegacySchemaVersion = 0
+)
+
+// GPathsFile is the content of a goto-paths file: the gpaths in a versioned envelope
+type GPathsFile struct {
+	Version int        `json:"version"`
+	Entries []GotoPath `json:"entries"`
+	Meta    FileMeta   `json:"meta"`
+}
+
+// FileMeta is the information about a goto-paths file
+type FileMeta struct {
+	// When the file was written (RFC3339)
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// newGPathsFile returns the envelope of the gpaths in the current version
+func newGPathsFile(gpaths []GotoPath, now time.Time) GPathsFile {
+	if gpaths == nil {
+		gpaths = []GotoPath{}
+	}
+	return GPathsFile{
+		Version: SchemaVersion,
+		Entries: gpaths,
+		Meta:    FileMeta{UpdatedAt: now.UTC().Format(time.RFC3339)},
+	}
+}
+
+// Migration upgrades the content of a goto-paths file from the version From to the version From+1
+type Migration struct {
+	From        int
+	Description string
+	Migrate     func(data []byte) ([]byte, error)
+}
+
+// The registered migrations by the version they upgrade
+var migrations = map[int]Migration{}
+
+// RegisterMigration adds a migration to the registry. It panics if there is already a migration from its version.
+func RegisterMigration(m Migration) {
+	if _, exists := migrations[m.From]; exists {
+		panic(fmt.Sprintf("there is already a migration from the version %d", m.From))
+	}
+	migrations[m.From] = m
+}
+
+func init() {
+	RegisterMigration(Migration{
+		From:        LegacySchemaVersion,
+		Description: "wrap the bare array of gpaths in the versioned envelope",
+		Migrate: func(data []byte) ([]byte, error) {
+			var buf bytes.Buffer
+			fmt.Fprintf(&buf, `{"version":%d,"entries":`, LegacySchemaVersion+1)
+			buf.Write(bytes.TrimSpace(data))
+			buf.WriteString(`,"meta":{}}`)
+			return buf.Bytes(), nil
+		},
+	})
+}
+
+// NewerSchemaError is returned when a goto-paths file written by a newer version of goto is going to be overwritten
+type NewerSchemaError struct {
+	File    string
+	Version int
+}
+
+func (e *NewerSchemaError) Error() string {
+	return fmt.Sprintf("the file \"%s\" was written by a newer version of goto (schema version %d, this version supports up to %d), update goto to change it",
+		e.File, e.Version, SchemaVersion)
+}
+
+// DetectSchemaVersion returns the version of the content of a goto-paths file
+// (LegacySchemaVersion for a bare array)
+func DetectSchemaVersion(data []byte) (int, error) {
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) > 0 && data[0] == '[':
+		return LegacySchemaVersion, nil
+
+	case len(data) > 0 && data[0] == '{':
+		var header struct {
+			Version *int `json:"version"`
+		}
+		if err := sonic.ConfigFastest.Unmarshal(data, &header); err != nil {
+			return 0, err
+		}
+		if header.Version == nil || *header.Version <= LegacySchemaVersion {
+			return 0, fmt.Errorf("the envelope doesn't have a valid version")
+		}
+		return *header.Version, nil
+
+	default:
+		return 0, fmt.Errorf("it must be a JSON array or a versioned envelope")
+	}
+}
+
+// MigrateGPathsData upgrades the content of a goto-paths file to SchemaVersion with the registered
+// migrations, and returns it with its original version. The content of a newer version is not changed.
+func MigrateGPathsData(data []byte) ([]byte, int, error) {
+	version, err := DetectSchemaVersion(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for v := version; v < SchemaVersion; v++ {
+		m, ok := migrations[v]
+		if !ok {
+			return nil, version, fmt.Errorf("there is no migration from the schema version %d", v)
+		}
+		if data, err = m.Migrate(data); err != nil {
+			return nil, version, fmt.Errorf("error migrating from the schema version %d (%s): %v", v, m.Description, err)
+		}
+	}
+	return data, version, nil
+}
+
+// DecodeGPaths parses the content of a goto-paths file (in any version) and returns its gpaths
+// and its original version. The gpaths of a newer version are decoded as well as possible.
+func DecodeGPaths(data []byte) ([]GotoPath, int, error) {
+	migrated, version, err := MigrateGPathsData(data)
+	if err != nil {
+		return nil, version, err
+	}
+
+	var file GPathsFile
+	if err := sonic.ConfigFastest.Unmarshal(migrated, &file); err != nil {
+		return nil, version, err
+	}
+	if file.Entries == nil {
+		file.Entries = []GotoPath{}
+	}
+	return file.Entries, version, nil
+}
+
+// PreMigrationBackupPath returns the copy of a goto-paths file before migrating it from the version
+func PreMigrationBackupPath(gotoPathsFile string, version int) string {
+	return fmt.Sprintf("%s.v%d.backup", gotoPathsFile, version)
+}
+
+// CheckWritableFile checks that the file doesn't exist or that it is not from a newer schema
+// version, because overwriting it would lose the information this version doesn't know.
+func CheckWritableFile(gotoPathsFile string) error {
+	return prepareOverwrite(gotoPathsFile, false)
+}
+
+// prepareOverwrite checks that the file can be overwritten (see CheckWritableFile). If keepOlder and
+// the file is of a previous version, it is copied before migrating it (see PreMigrationBackupPath).
+func prepareOverwrite(gotoPathsFile string, keepOlder bool) error {
+	data, err := os.ReadFile(gotoPathsFile)
+	if err != nil {
+		return nil
+	}
+
+	// A file that can't be parsed is overwritten without a copy (see LastGoodCopyPath)
+	version, err := DetectSchemaVersion(data)
+	switch {
+	case err != nil:
+		return nil
+	case version > SchemaVersion:
+		return &NewerSchemaError{File: gotoPathsFile, Version: version}
+	case version < SchemaVersion && keepOlder:
+		if err := os.WriteFile(PreMigrationBackupPath(gotoPathsFile, version), data, 0600); err != nil {
+			return fmt.Errorf("can't keep the copy of \"%s\" before migrating it: %v", gotoPathsFile, err)
+		}
+	}
+	return nil
+}
@@ -4,7 +4,7 @@ import (
 	"strconv"
 )
 
-// Return the path of a: Index (number) or an Abbreviation.
+// Return the path (expanded) of a: Index (number) or an Abbreviation.
 // If is not an abbreviation or a valid index return the same input
 func GetPathFromIndexOrAbbreviation(gpaths []GotoPath, arg string) (string, bool) {
 
@@ -14,13 +14,13 @@ func GetPathFromIndexOrAbbreviation(gpaths []GotoPath, arg string) (string, bool
 		//I already know that "arg" is a number
 		pathNumber, _ := strconv.Atoi(arg)
 
-		return gpaths[pathNumber].Path, true
+		return gpaths[pathNumber].ExpandedPath(), true
 	}
 
 	//If not a number, check if is an abbreviation
 	for _, gpath := range gpaths {
 		if arg == gpath.Abbreviation {
-			return gpath.Path, true
+			return gpath.ExpandedPath(), true
 		}
 	}
 
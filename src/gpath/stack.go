@@ -0,0 +1,100 @@
+package gpath
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/bytedance/sonic"
+)
+
+// Number of directories kept in a directory stack, the oldest are removed
+const StackMaxEntries = 50
+
+// DirStack is the directory stack of a shell session. The directory at the position is the current
+// one, the directories before it can be visited with back and the directories after it with forward.
+type DirStack struct {
+	Entries  []string `json:"entries"`
+	Position int      `json:"position"`
+}
+
+// Current returns the current directory of the stack (empty if the stack is empty)
+func (s *DirStack) Current() string {
+	if len(s.Entries) == 0 {
+		return ""
+	}
+	return s.Entries[s.Position]
+}
+
+// Push adds a directory after the current one and makes it the current directory.
+// The directories after the current one (the forward directories) are discarded.
+func (s *DirStack) Push(dir string) {
+	if dir == s.Current() {
+		return
+	}
+
+	if len(s.Entries) > 0 {
+		s.Entries = s.Entries[:s.Position+1]
+	}
+	s.Entries = append(s.Entries, dir)
+
+	if len(s.Entries) > StackMaxEntries {
+		s.Entries = s.Entries[len(s.Entries)-StackMaxEntries:]
+	}
+	s.Position = len(s.Entries) - 1
+}
+
+// Move moves the current directory n positions (negative is back) and returns the new current directory
+func (s *DirStack) Move(n int) (string, error) {
+	return s.Jump(s.Position + n)
+}
+
+// Jump makes the directory at the index the current directory and returns it
+func (s *DirStack) Jump(index int) (string, error) {
+	if len(s.Entries) == 0 {
+		return "", fmt.Errorf("the directory stack is empty")
+	}
+
+	if index < 0 {
+		return "", fmt.Errorf("can't go back %d directories, there are only %d", s.Position-index, s.Position)
+	}
+	if index >= len(s.Entries) {
+		return "", fmt.Errorf("can't go forward %d directories, there are only %d", index-s.Position, len(s.Entries)-1-s.Position)
+	}
+
+	s.Position = index
+	return s.Entries[index], nil
+}
+
+// Save the directory stack in the stack file
+func SaveStackFile(stack DirStack, stackFile string) error {
+	data, err := sonic.ConfigDefault.Marshal(stack)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(stackFile, data, 0600)
+}
+
+// Load the stack file. If the file doesn't exist, the stack is empty
+func LoadStackFile(stackFile string) (DirStack, error) {
+	stack := DirStack{Entries: []string{}}
+
+	file, err := os.Open(stackFile)
+	if os.IsNotExist(err) {
+		return stack, nil
+	}
+	if err != nil {
+		return stack, fmt.Errorf("error reading directory stack file")
+	}
+	defer file.Close()
+
+	if err := sonic.ConfigFastest.NewDecoder(bufio.NewReader(file)).Decode(&stack); err != nil {
+		return stack, fmt.Errorf("error parsing directory stack file")
+	}
+
+	if stack.Position < 0 || stack.Position >= len(stack.Entries) {
+		stack.Position = max(len(stack.Entries)-1, 0)
+	}
+
+	return stack, nil
+}
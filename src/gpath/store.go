@@ -3,16 +3,42 @@ package gpath
 import (
 	"bufio"
 	"fmt"
+	"goto/src/config"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/bytedance/sonic"
 )
 
-// Create default GotoPath entries
+// Create default GotoPath entries: the ones of the default_entries setting or, if it is not set,
+// the home and the directory of the file. The configured entries that aren't a directory are skipped.
 func createDefaultGotoPathsFile(gotoPathsFile string) ([]GotoPath, error) {
 	var gpaths []GotoPath
 
+	if entries := config.Get().DefaultEntries; len(entries) > 0 {
+		for _, entry := range entries {
+			// The portable paths are stored without expanding them
+			path := strings.TrimSpace(entry.Path)
+			if !IsPortable(path) {
+				var err error
+				if path, err = ValidPath(path); err != nil {
+					continue
+				}
+			} else if _, err := ValidPath(ExpandPath(path)); err != nil {
+				continue
+			}
+
+			gpaths = append(gpaths, GotoPath{
+				Path:         path,
+				Abbreviation: strings.TrimSpace(entry.Abbreviation),
+			})
+		}
+		return gpaths, nil
+	}
+
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return nil, err
@@ -32,6 +58,11 @@ func createDefaultGotoPathsFile(gotoPathsFile string) ([]GotoPath, error) {
 	return gpaths, nil
 }
 
+// DefaultGPaths returns the gpaths written in a new goto-paths file (see CreateGotoPathsFile)
+func DefaultGPaths(gotoPathsFile string) ([]GotoPath, error) {
+	return createDefaultGotoPathsFile(gotoPathsFile)
+}
+
 // Create the config file if not already exists
 func CreateGotoPathsFile(gotoPathsFile string) error {
 	if _, err := os.Stat(gotoPathsFile); err == nil {
@@ -50,53 +81,150 @@ func CreateGotoPathsFile(gotoPathsFile string) error {
 	return SaveGPathsFile(gpaths, gotoPathsFile)
 }
 
-// Validate the array (using CheckRepeatedItems) and create a paths file from directory array
+// CorruptedFileError is returned when a goto-paths file can't be parsed
+// and there is a last valid copy of it to recover.
+type CorruptedFileError struct {
+	File     string
+	LastGood string
+}
+
+func (e *CorruptedFileError) Error() string {
+	return fmt.Sprintf("error parsing config file \"%s\", the last valid copy is \"%s\"", e.File, e.LastGood)
+}
+
+// Return the path of the last valid copy of a goto-paths file
+func LastGoodCopyPath(gotoPathsFile string) string {
+	return gotoPathsFile + ".last-good"
+}
+
+// Validate the array (using CheckRepeatedItems) and create a paths file from directory array.
+// The file is written atomically (a temporal file in the same directory is renamed to the file)
+// and, after it is written, it is also kept as the last valid copy (see LastGoodCopyPath) if the file
+// already existed (a new file, like a backup, doesn't need it).
+// A file of a newer schema version is not overwritten (see CheckWritableFile) and a file of a
+// previous version is migrated after keeping a copy of it (see PreMigrationBackupPath). It must
+// be called holding the lock of the file (see LockFile).
 func SaveGPathsFile(gpaths []GotoPath, gotoPathsFile string) error {
 
 	if err := CheckRepeatedItems(gpaths); err != nil {
 		return err
 	}
 
-	// Open the file for writing (create or truncate)
-	file, err := os.OpenFile(gotoPathsFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err := prepareOverwrite(gotoPathsFile, true); err != nil {
+		return err
+	}
+
+	_, statErr := os.Stat(gotoPathsFile)
+	if err := writeGPathsFile(gpaths, gotoPathsFile); err != nil {
+		return err
+	}
+
+	// The saved gpaths are the last valid copy, so a corruption of the file doesn't lose the last change
+	if statErr != nil {
+		return nil
+	}
+	return writeGPathsFile(gpaths, LastGoodCopyPath(gotoPathsFile))
+}
+
+// Write the array to the file atomically (in the versioned envelope): encode it in a temporal file, fsync and rename it
+func writeGPathsFile(gpaths []GotoPath, gotoPathsFile string) error {
+	return writeFileAtomic(gotoPathsFile, func(w io.Writer) error {
+		enc := sonic.ConfigDefault.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		return enc.Encode(newGPathsFile(gpaths, time.Now()))
+	})
+}
+
+// Write the file atomically: write it in a temporal file with encode, fsync and rename it, so the file is
+// never left half written
+func writeFileAtomic(name string, encode func(w io.Writer) error) error {
+
+	// The temporal file must be in the same directory to rename it
+	file, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".tmp-*")
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	tmpName := file.Name()
+
+	// If something fails, remove the temporal file
+	success := false
+	defer func() {
+		if !success {
+			file.Close()
+			os.Remove(tmpName)
+		}
+	}()
+
+	if err := file.Chmod(0600); err != nil {
+		return err
+	}
 
 	// Use Buffered Writer for efficiency as suggested in the blog
 	writer := bufio.NewWriter(file)
 
 	// Encode directly to the stream
-	enc := sonic.ConfigDefault.NewEncoder(writer)
-	enc.SetIndent("", "\t")
-	if err := enc.Encode(gpaths); err != nil {
+	if err := encode(writer); err != nil {
 		return err
 	}
 
-	// Flush the buffer to ensure all data is written
-	return writer.Flush()
+	// Flush the buffer and sync the file to ensure all data is written before the rename
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpName, name); err != nil {
+		return err
+	}
+
+	success = true
+	return nil
 }
 
-// Load config file into an array
+// Load config file into an array.
+// If the file can't be parsed and there is a valid last copy, a CorruptedFileError is returned.
 func LoadGPathsFile(gpaths *[]GotoPath, gotoPathsFile string) error {
+	loaded, err := ReadGPathsFile(gotoPathsFile)
+	if err != nil {
+		return err
+	}
+	*gpaths = loaded
+
+	//If all is OK, check dir and return
+	return CheckRepeatedItems(*gpaths)
+}
 
-	// Open the File
-	file, err := os.Open(gotoPathsFile)
+// ReadGPathsFile parses the config file without checking the repeated items (e.g. to report them).
+// A file of a previous schema version (e.g. a legacy bare array) is migrated in memory, the file is
+// migrated when it is saved (see SaveGPathsFile). If the file can't be parsed and there is a valid
+// last copy, a CorruptedFileError is returned.
+func ReadGPathsFile(gotoPathsFile string) ([]GotoPath, error) {
+
+	// Read the File
+	data, err := os.ReadFile(gotoPathsFile)
 	if err != nil {
-		return fmt.Errorf("error reading config file")
+		return nil, fmt.Errorf("error reading config file")
 	}
-	defer file.Close()
 
-	// Use Buffered Reader for efficiency
-	reader := bufio.NewReader(file)
+	// Load the Paths of any schema version
+	gpaths, _, err := DecodeGPaths(data)
+	if err != nil {
+		lastGood := LastGoodCopyPath(gotoPathsFile)
+
+		var copyGPaths []GotoPath
+		if gotoPathsFile != lastGood && LoadGPathsFile(&copyGPaths, lastGood) == nil {
+			return nil, &CorruptedFileError{File: gotoPathsFile, LastGood: lastGood}
+		}
 
-	// Load the Paths using sonic's stream decoder
-	dec := sonic.ConfigFastest.NewDecoder(reader)
-	if err := dec.Decode(gpaths); err != nil {
-		return fmt.Errorf("error parsing config file")
+		return nil, fmt.Errorf("error parsing config file")
 	}
 
-	//If all is OK, check dir and return
-	return CheckRepeatedItems(*gpaths)
+	return gpaths, nil
 }
@@ -0,0 +1,38 @@
+package gpath
+
+import "time"
+
+// Expired checks if the gpath has an expiration time and it has passed
+func (d GotoPath) Expired(now time.Time) bool {
+	return d.ExpiresAt != 0 && now.Unix() >= d.ExpiresAt
+}
+
+// VisibleIn checks if the gpath can be seen from the shell session (it is of all the sessions or of that one)
+func (d GotoPath) VisibleIn(session string) bool {
+	return d.Session == "" || d.Session == session
+}
+
+// Permanent returns the gpath without the expiration time and the session
+func (d GotoPath) Permanent() GotoPath {
+	d.ExpiresAt = 0
+	d.Session = ""
+	return d
+}
+
+// SplitTemporalGPaths splits the gpaths of a temporal file in the ones visible from the session and
+// the ones of other sessions (hidden). The expired gpaths are discarded.
+func SplitTemporalGPaths(gpaths []GotoPath, session string, now time.Time) (visible, hidden []GotoPath) {
+	visible = []GotoPath{}
+	hidden = []GotoPath{}
+	for _, gp := range gpaths {
+		switch {
+		case gp.Expired(now):
+			continue
+		case gp.VisibleIn(session):
+			visible = append(visible, gp)
+		default:
+			hidden = append(hidden, gp)
+		}
+	}
+	return visible, hidden
+}
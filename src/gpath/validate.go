@@ -2,10 +2,13 @@ package gpath
 
 import (
 	"fmt"
+	"goto/src/config"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // ValidPathVar validates and cleans a path in-place.
@@ -98,6 +101,40 @@ func ValidAbbreviationVar(abbv *string) error {
 	return nil
 }
 
+// ValidNewAbbreviationVar validates and cleans in-place an abbreviation that is going to be stored.
+// Besides the rules of ValidAbbreviationVar, it checks the abbreviation_pattern and
+// abbreviation_max_length settings (they don't apply to the abbreviations already stored).
+func ValidNewAbbreviationVar(abbv *string) error {
+	validAbbv := *abbv
+	if err := ValidAbbreviationVar(&validAbbv); err != nil {
+		return err
+	}
+
+	settings := config.Get()
+	if max := settings.AbbreviationMaxLength; max > 0 && utf8.RuneCountInString(validAbbv) > max {
+		return fmt.Errorf("the Abbreviation \"%s\" is longer than %d characters (abbreviation_max_length setting)", validAbbv, max)
+	}
+
+	if pattern := settings.AbbreviationPattern; pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid abbreviation_pattern setting: %v", err)
+		}
+		if !re.MatchString(validAbbv) {
+			return fmt.Errorf("the Abbreviation \"%s\" doesn't match the pattern \"%s\" (abbreviation_pattern setting)", validAbbv, pattern)
+		}
+	}
+
+	*abbv = validAbbv
+	return nil
+}
+
+// ValidNewAbbreviation is a wrapper around ValidNewAbbreviationVar for convenience.
+func ValidNewAbbreviation(abbv string) (string, error) {
+	err := ValidNewAbbreviationVar(&abbv)
+	return abbv, err
+}
+
 // ValidAbbreviation is a wrapper around ValidAbbreviationVar for convenience.
 // It takes a string value (not a pointer), validates it, and returns the cleaned abbreviation.
 // Use this if you prefer returning a new value rather than modifying a variable in-place.
@@ -106,6 +143,53 @@ func ValidAbbreviation(abbv string) (string, error) {
 	return abbv, err
 }
 
+// ValidTagsVar validates and cleans a list of tags in-place.
+// It receives a pointer to the slice, so if the validation succeeds,
+// it overwrites the original variable with the trimmed tags without duplicates.
+//
+// Steps:
+// - Check that any tag doesn't be empty
+// - Check that any tag don't contain any space or comma
+// - Remove the repeated tags
+func ValidTagsVar(tags *[]string) error {
+
+	validTags := []string{}
+	for _, tag := range *tags {
+		tag = strings.TrimSpace(tag)
+
+		if len(tag) < 1 {
+			return fmt.Errorf("the Tags can't be empty or be blank space")
+		}
+
+		if strings.ContainsAny(tag, " ,") {
+			return fmt.Errorf("the Tag \"%s\" can't contain any space or comma", tag)
+		}
+
+		repeated := false
+		for _, t := range validTags {
+			if strings.EqualFold(t, tag) {
+				repeated = true
+				break
+			}
+		}
+
+		if !repeated {
+			validTags = append(validTags, tag)
+		}
+	}
+
+	// "Save" the value of the ValidTags in the Tags slice passed
+	*tags = validTags
+	return nil
+}
+
+// ValidTags is a wrapper around ValidTagsVar for convenience.
+// It takes a slice (not a pointer), validates it, and returns the cleaned tags.
+func ValidTags(tags []string) ([]string, error) {
+	err := ValidTagsVar(&tags)
+	return tags, err
+}
+
 // IsValidIndex checks if an index is valid (a number within the range [0, length-1]).
 func IsValidIndex(length int, index string) error {
 	indx, err := strconv.Atoi(index)
@@ -124,29 +208,61 @@ func IsValidIndex(length int, index string) error {
 	return nil
 }
 
-// Check that the any gpath has the same Path or same Abbreviation that other
+// Check that the any gpath has the same Path or same Abbreviation that other.
+// The gpaths of a session (see GotoPath.Session) only can't repeat the gpaths of all the sessions
+// and the ones of the same session, because each session only sees those ones.
 func CheckRepeatedItems(gpaths []GotoPath) error {
 
 	if len(gpaths) == 0 {
 		return fmt.Errorf("the config file is empty")
 	}
 
-	pathMap := make(map[string]int)
-	abbrMap := make(map[string]int)
+	// The paths and abbreviations by session ("" for the gpaths of all the sessions)
+	pathMaps := map[string]map[string]int{}
+	abbrMaps := map[string]map[string]int{}
+
+	check := func(i int, session string) error {
+		gpath := gpaths[i]
+		scopes := []string{""}
+		if session != "" {
+			scopes = append(scopes, session)
+		}
+
+		for _, scope := range scopes {
+			// Check for duplicate path (a portable path is the same that its expansion)
+			if _, exists := pathMaps[scope][gpath.ExpandedPath()]; exists {
+				return fmt.Errorf("the path: \"%v\" already exists", gpath.Path)
+			}
 
-	for i, gpath := range gpaths {
+			// Check for duplicate abbreviation
+			if idx, exists := abbrMaps[scope][gpath.Abbreviation]; exists {
+				return fmt.Errorf("the Path: \"%v\"(index %v) have the same Abbreviation that \"%v\"(index %v)", gpath.Path, i, gpaths[idx].Path, idx)
+			}
+		}
 
-		// Check for duplicate path
-		if _, exists := pathMap[gpath.Path]; exists {
-			return fmt.Errorf("the path: \"%v\" already exists", gpath.Path)
+		if pathMaps[session] == nil {
+			pathMaps[session] = map[string]int{}
+			abbrMaps[session] = map[string]int{}
 		}
-		pathMap[gpath.Path] = i
+		pathMaps[session][gpath.ExpandedPath()] = i
+		abbrMaps[session][gpath.Abbreviation] = i
+		return nil
+	}
 
-		// Check for duplicate abbreviation
-		if idx, exists := abbrMap[gpath.Abbreviation]; exists {
-			return fmt.Errorf("the Path: \"%v\"(index %v) have the same Abbreviation that \"%v\"(index %v)", gpath.Path, i, gpaths[idx].Path, idx)
+	// First the gpaths of all the sessions, so the ones of each session are checked against them
+	for i := range gpaths {
+		if gpaths[i].Session == "" {
+			if err := check(i, ""); err != nil {
+				return err
+			}
+		}
+	}
+	for i := range gpaths {
+		if gpaths[i].Session != "" {
+			if err := check(i, gpaths[i].Session); err != nil {
+				return err
+			}
 		}
-		abbrMap[gpath.Abbreviation] = i
 	}
 
 	return nil
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lines of context around the changes of an unified diff
+const diffContext = 3
+
+// A line of the edit script: ' ' (equal), '-' (deleted) or '+' (inserted)
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// UnifiedDiff returns the unified diff (like "diff -u") between the texts, or an empty string if
+// they are equal. The names are used in the header of the diff.
+func UnifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	script := diffLines(splitLines(oldText), splitLines(newText))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+
+	// Positions (0-based) of each line of the script in the old and the new text
+	oldPos := make([]int, len(script)+1)
+	newPos := make([]int, len(script)+1)
+	for i, l := range script {
+		oldPos[i+1], newPos[i+1] = oldPos[i], newPos[i]
+		if l.op != '+' {
+			oldPos[i+1]++
+		}
+		if l.op != '-' {
+			newPos[i+1]++
+		}
+	}
+
+	for i := 0; i < len(script); {
+		if script[i].op == ' ' {
+			i++
+			continue
+		}
+
+		// The hunk starts with the context before the change and ends when there are more
+		// than 2*context equal lines until the next change
+		start := max(i-diffContext, 0)
+		end := i
+		for equal := 0; end < len(script) && equal <= 2*diffContext; end++ {
+			if script[end].op == ' ' {
+				equal++
+			} else {
+				equal = 0
+			}
+		}
+		// Remove the equal lines after the context of the last change
+		for end > i && script[end-1].op == ' ' {
+			end--
+		}
+		end = min(end+diffContext, len(script))
+
+		oldCount, newCount := oldPos[end]-oldPos[start], newPos[end]-newPos[start]
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(oldPos[start], oldCount), hunkRange(newPos[start], newCount))
+		for _, l := range script[start:end] {
+			sb.WriteByte(l.op)
+			sb.WriteString(l.text)
+			sb.WriteByte('\n')
+		}
+
+		i = end
+	}
+
+	return sb.String()
+}
+
+// hunkRange returns the range of a hunk header (the start is 1-based, or the previous line if it is empty)
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits the text in lines (without the last empty line if the text ends with a new line)
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines returns the edit script from a to b using the longest common subsequence
+func diffLines(a, b []string) []diffLine {
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	script := make([]diffLine, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			script = append(script, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			script = append(script, diffLine{'-', a[i]})
+			i++
+		default:
+			script = append(script, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		script = append(script, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		script = append(script, diffLine{'+', b[j]})
+	}
+
+	return script
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"goto/src/gpath"
 	"strconv"
 
@@ -12,6 +13,17 @@ const (
 	FlagAbbreviation string = "abbv"
 	FlagIndex        string = "indx"
 	FlagTemporal     string = "temporal"
+	FlagTag          string = "tag"
+	FlagTagMode      string = "tag-mode"
+	FlagDescription  string = "description"
+)
+
+const (
+	// The gpath must have all the tags
+	TagModeAnd string = "and"
+
+	// The gpath must have at least one of the tags
+	TagModeOr string = "or"
 )
 
 // Check if the flag (key) was passed
@@ -39,6 +51,34 @@ func TemporalFlagPassed(cmd *cobra.Command) bool {
 	return cmd.Flags().Changed(FlagTemporal)
 }
 
+// Check if the FlagTag was passed
+func TagFlagPassed(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed(FlagTag)
+}
+
+// Returns the tags of the FlagTag and if all of them must match (FlagTagMode) already validated
+func GetTagsFilter(cmd *cobra.Command) ([]string, bool) {
+	tags, err := cmd.Flags().GetStringSlice(FlagTag)
+	cobra.CheckErr(err)
+
+	cobra.CheckErr(gpath.ValidTagsVar(&tags))
+
+	mode, err := cmd.Flags().GetString(FlagTagMode)
+	cobra.CheckErr(err)
+
+	if mode != TagModeAnd && mode != TagModeOr {
+		cobra.CheckErr(fmt.Errorf("the tag mode must be \"%s\" or \"%s\"", TagModeAnd, TagModeOr))
+	}
+
+	return tags, mode == TagModeAnd
+}
+
+// Add the FlagTag and FlagTagMode flags to filter by tags
+func AddTagsFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice(FlagTag, nil, "Filter by tag (can be repeated or separated by commas)")
+	cmd.Flags().String(FlagTagMode, TagModeAnd, "How the tags are matched: \""+TagModeAnd+"\" (all of them) or \""+TagModeOr+"\" (any of them)")
+}
+
 // Returns the value of the FlagPath already validated
 func GetPath(cmd *cobra.Command) string {
 	path, err := cmd.Flags().GetString(FlagPath)
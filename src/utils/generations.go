@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"fmt"
+	"goto/src/config"
+	"goto/src/gpath"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// Name of the directory (inside the config directory) where the automatic backups are stored.
+	BACKUPS_DIR = "backups"
+)
+
+// Return the number of automatic backups to keep (the backup_generations setting)
+func GetBackupGenerations() int {
+	return config.Get().Generations()
+}
+
+// Return the directory of the automatic backups
+func GetBackupsDir() string {
+	return filepath.Join(configDir, BACKUPS_DIR)
+}
+
+// SnapshotGPaths saves the goto-paths file of the profile in use as a new automatic backup
+func SnapshotGPaths() error {
+	return gpath.SnapshotFile(gotoPathsFile, GetBackupsDir(), currentProfile, GetBackupGenerations(), time.Now())
+}
+
+// Return the automatic backups of the profile in use, the newest first
+func GetGenerations() ([]gpath.Generation, error) {
+	return gpath.ListGenerations(GetBackupsDir(), currentProfile)
+}
+
+// Return the file of the automatic backup n of the profile in use (0 is the newest)
+func GetGenerationFilePath(n int) (string, error) {
+	generations, err := GetGenerations()
+	if err != nil {
+		return "", err
+	}
+
+	if n < 0 || n >= len(generations) {
+		return "", fmt.Errorf("the backup generation %v doesn't exist (there are %v generations)", n, len(generations))
+	}
+
+	return generations[n].File, nil
+}
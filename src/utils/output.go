@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"fmt"
+	"goto/src/gpath"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// Name of the global flag to select the output format
+	FlagOutput string = "output"
+
+	// The default output format, human readable
+	OutputText string = "text"
+
+	// A JSON array with all the entries
+	OutputJSON string = "json"
+
+	// A JSON object by line (newline delimited JSON)
+	OutputNDJSON string = "ndjson"
+
+	// A line by entry with the fields separated by tabs
+	// (index, abbreviation, path, tags, description, valid, error)
+	OutputTSV string = "tsv"
+)
+
+// OutputEntry is the representation of a gpath in the machine-readable output formats
+type OutputEntry struct {
+	// The index of the gpath in the goto-paths file (-1 if the path is not a gpath)
+	Index        int      `json:"index"`
+	Abbreviation string   `json:"abbreviation"`
+	Path         string   `json:"path"`
+	Tags         []string `json:"tags"`
+	Description  string   `json:"description"`
+
+	// The expansion of a portable path ("~/..." or "$VAR/..."), only present for the portable paths
+	ExpandedPath string `json:"expanded_path,omitempty"`
+
+	// The file of the gpath when it comes from a project file or a base profile
+	Source string `json:"source,omitempty"`
+
+	// If the gpath is of the temporal file (only in the lists of both files), when it expires
+	// (RFC 3339) and the shell session that can see it
+	Temporal  bool   `json:"temporal,omitempty"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+	Session   string `json:"session,omitempty"`
+
+	// The layer of the gpath (temporal, project or permanent) and the layer that shadows it,
+	// only in the resolved paths and in the lists of the layers
+	Layer      string `json:"layer,omitempty"`
+	ShadowedBy string `json:"shadowed_by,omitempty"`
+
+	// When the automatic backup was saved (RFC 3339), only in the list of the automatic backups
+	SavedAt string `json:"saved_at,omitempty"`
+
+	// Validation status, only present in the output of valid-paths
+	Valid *bool  `json:"valid,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// NewOutputEntry creates an OutputEntry from a gpath and its index
+func NewOutputEntry(index int, gp gpath.GotoPath) OutputEntry {
+	tags := gp.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	entry := OutputEntry{
+		Index:        index,
+		Abbreviation: gp.Abbreviation,
+		Path:         gp.Path,
+		Tags:         tags,
+		Description:  gp.Description,
+		Source:       gp.Source,
+		Session:      gp.Session,
+	}
+
+	if gp.ExpiresAt != 0 {
+		entry.ExpiresAt = time.Unix(gp.ExpiresAt, 0).Format(time.RFC3339)
+	}
+
+	if expanded := gp.ExpandedPath(); expanded != gp.Path {
+		entry.ExpandedPath = expanded
+	}
+	return entry
+}
+
+// WithStatus returns the entry with the validation status of the gpath (err == nil means valid)
+func (e OutputEntry) WithStatus(err error) OutputEntry {
+	valid := err == nil
+	e.Valid = &valid
+	if err != nil {
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// Returns the value of the FlagOutput already validated.
+// A value that is not a known format must be a Go template (e.g. "{{.Path}}").
+func GetOutputFormat(cmd *cobra.Command) string {
+	format, err := cmd.Flags().GetString(FlagOutput)
+	if err != nil || format == "" {
+		return OutputText
+	}
+
+	cobra.CheckErr(ValidOutputFormat(format))
+	return format
+}
+
+// ValidOutputFormat checks that the format is text, json, ndjson, tsv or a valid Go template
+func ValidOutputFormat(format string) error {
+	switch format {
+	case OutputText, OutputJSON, OutputNDJSON, OutputTSV:
+		return nil
+	}
+
+	if !strings.Contains(format, "{{") {
+		return fmt.Errorf("invalid output format \"%s\" (must be %s, %s, %s, %s or a Go template like \"{{.Path}}\")",
+			format, OutputText, OutputJSON, OutputNDJSON, OutputTSV)
+	}
+
+	if _, err := template.New("output").Parse(format); err != nil {
+		return fmt.Errorf("invalid output template: %v", err)
+	}
+
+	return nil
+}
+
+// WriteOutput writes the entries in the format (json, ndjson, tsv or a Go template executed by entry)
+func WriteOutput(w io.Writer, format string, entries []OutputEntry) error {
+	if entries == nil {
+		entries = []OutputEntry{}
+	}
+
+	switch format {
+	case OutputJSON:
+		enc := sonic.ConfigDefault.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		return enc.Encode(entries)
+
+	case OutputNDJSON:
+		enc := sonic.ConfigDefault.NewEncoder(w)
+		for _, e := range entries {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case OutputTSV:
+		for _, e := range entries {
+			valid := ""
+			if e.Valid != nil {
+				valid = strconv.FormatBool(*e.Valid)
+			}
+
+			fields := []string{strconv.Itoa(e.Index), e.Abbreviation, e.Path, strings.Join(e.Tags, ","), e.Description, valid, e.Error}
+			for i := range fields {
+				fields[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(fields[i])
+			}
+
+			if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case OutputText:
+		return fmt.Errorf("the text output format is not a machine-readable format")
+
+	default:
+		tmpl, err := template.New("output").Parse(format)
+		if err != nil {
+			return fmt.Errorf("invalid output template: %v", err)
+		}
+
+		for _, e := range entries {
+			if err := tmpl.Execute(w, e); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,210 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"goto/src/gpath"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+const (
+	// Name of the profile that uses the goto-paths file of the config directory.
+	DEFAULT_PROFILE = "default"
+
+	// Name of the directory (inside the config directory) where the profiles are stored.
+	PROFILES_DIR = "profiles"
+
+	// Name of the file where the current profile and the bases of the profiles are stored.
+	PROFILES_FILE_NAME = "profiles.json"
+
+	// Environment variable to select the profile (the --profile flag takes precedence).
+	PROFILE_ENV_VAR = "GOTO_PROFILE"
+
+	// Name of the flag to select the profile.
+	FlagProfile string = "profile"
+)
+
+// Valid names of the profiles
+var profileNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Name of the profile in use
+var currentProfile = DEFAULT_PROFILE
+
+// The warning of the last UseProfile (e.g. the selected profile doesn't exist)
+var profileWarning string
+
+// ProfilesConfig is the content of the profiles file
+type ProfilesConfig struct {
+	// The profile selected with "goto profile switch"
+	Current string `json:"current"`
+
+	// The base of each profile, the paths of a profile fall through to its base
+	Bases map[string]string `json:"bases,omitempty"`
+}
+
+// ValidProfileName checks that the name of the profile only contains letters, numbers, "-" or "_"
+func ValidProfileName(name string) error {
+	if !profileNameRegexp.MatchString(name) {
+		return fmt.Errorf("the profile name \"%s\" is invalid (only letters, numbers, \"-\" and \"_\" are allowed)", name)
+	}
+	return nil
+}
+
+// Return the path of the goto-paths file of a profile
+func GetProfileFilePath(name string) string {
+	if name == DEFAULT_PROFILE {
+		return filepath.Join(configDir, GOTO_FILE_NAME)
+	}
+	return filepath.Join(configDir, PROFILES_DIR, name+".json")
+}
+
+// Check if the profile exists
+func ProfileExists(name string) bool {
+	_, err := os.Stat(GetProfileFilePath(name))
+	return err == nil
+}
+
+// UseProfile selects the profile used by all the operations over the goto-paths file.
+// If the name is empty, the profile is taken from the GOTO_PROFILE environment variable,
+// then from the profile selected with "goto profile switch" and then the default profile.
+// If the profile selected with "goto profile switch" doesn't exist, the default profile is used
+// and the warning is returned by ProfileWarning.
+func UseProfile(name string) error {
+	profileWarning = ""
+
+	if name == "" {
+		name = os.Getenv(PROFILE_ENV_VAR)
+	}
+
+	if name == "" {
+		config, err := LoadProfilesConfig()
+		if err != nil {
+			return err
+		}
+		name = config.Current
+
+		if name != "" && ValidProfileName(name) == nil && !ProfileExists(name) {
+			profileWarning = fmt.Sprintf("the selected profile \"%s\" doesn't exist, using the \"%s\" profile (select other with \"goto profile switch\")", name, DEFAULT_PROFILE)
+			name = DEFAULT_PROFILE
+		}
+	}
+
+	if name == "" {
+		name = DEFAULT_PROFILE
+	}
+
+	if err := ValidProfileName(name); err != nil {
+		return err
+	}
+
+	if !ProfileExists(name) {
+		return fmt.Errorf("the profile \"%s\" doesn't exist", name)
+	}
+
+	currentProfile = name
+	gotoPathsFile = GetProfileFilePath(name)
+	gotoPathsFileBackup = filepath.Clean(gotoPathsFile + ".backup")
+	return nil
+}
+
+// ProfileWarning returns the warning of the last UseProfile (empty if there isn't any)
+func ProfileWarning() string {
+	return profileWarning
+}
+
+// Return the name of the profile in use
+func GetCurrentProfile() string {
+	return currentProfile
+}
+
+// Return the names of all the profiles (sorted, the default profile first)
+func GetProfiles() ([]string, error) {
+	profiles := []string{DEFAULT_PROFILE}
+
+	entries, err := os.ReadDir(filepath.Join(configDir, PROFILES_DIR))
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, e := range entries {
+		name, isJSON := strings.CutSuffix(e.Name(), ".json")
+		if !e.IsDir() && isJSON && ValidProfileName(name) == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return append(profiles, names...), nil
+}
+
+// Return the chain of profiles used to resolve the paths of a profile: the profile, its base,
+// the base of its base... An error is returned if the chain has a cycle.
+func GetProfileChain(name string) ([]string, error) {
+	config, err := LoadProfilesConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	chain := []string{name}
+	for base, ok := config.Bases[name]; ok && base != ""; base, ok = config.Bases[base] {
+		for _, p := range chain {
+			if p == base {
+				return nil, fmt.Errorf("the bases of the profile \"%s\" have a cycle", chain[0])
+			}
+		}
+		chain = append(chain, base)
+	}
+
+	return chain, nil
+}
+
+// Load a goto-paths file of a profile in the gpaths array
+func LoadProfileGPaths(name string) ([]gpath.GotoPath, error) {
+	gpaths := &[]gpath.GotoPath{}
+	err := gpath.LoadGPathsFile(gpaths, GetProfileFilePath(name))
+	return *gpaths, err
+}
+
+// Load the profiles file, if the file doesn't exist the config is empty
+func LoadProfilesConfig() (ProfilesConfig, error) {
+	config := ProfilesConfig{Bases: map[string]string{}}
+
+	file, err := os.Open(filepath.Join(configDir, PROFILES_FILE_NAME))
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("error reading profiles file")
+	}
+	defer file.Close()
+
+	if err := sonic.ConfigFastest.NewDecoder(bufio.NewReader(file)).Decode(&config); err != nil {
+		return config, fmt.Errorf("error parsing profiles file")
+	}
+
+	if config.Bases == nil {
+		config.Bases = map[string]string{}
+	}
+
+	return config, nil
+}
+
+// Overwrite the profiles file with the config
+func UpdateProfilesConfig(config ProfilesConfig) error {
+	data, err := sonic.ConfigDefault.MarshalIndent(config, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(configDir, PROFILES_FILE_NAME), data, 0600)
+}
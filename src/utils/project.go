@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"fmt"
+	"goto/src/gpath"
+	"os"
+	"path/filepath"
+)
+
+// Name of the project files, discovered from the working directory upward.
+const PROJECT_FILE_NAME = ".goto.json"
+
+// FindProjectFiles returns the project files from the directory upward (the nearest first).
+func FindProjectFiles(dir string) []string {
+	files := []string{}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return files
+	}
+
+	for {
+		file := filepath.Join(dir, PROJECT_FILE_NAME)
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			files = append(files, file)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return files
+		}
+		dir = parent
+	}
+}
+
+// Return the nearest project file from the working directory upward.
+// If there is not any, the path of a new project file in the working directory is returned.
+func GetNearestProjectFile() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	if files := FindProjectFiles(cwd); len(files) > 0 {
+		return files[0], nil
+	}
+
+	return filepath.Join(cwd, PROJECT_FILE_NAME), nil
+}
+
+// The project files that can't be loaded and were already reported (see LoadProjectGPaths)
+var skippedProjectFiles = map[string]bool{}
+
+// Load the project files from the working directory upward (the nearest first).
+// A project file that can't be loaded (e.g. a broken one in a parent directory) is skipped with a
+// warning in the stderr, so it doesn't stop the resolution of the other gpaths.
+func LoadProjectGPaths() ([][]gpath.GotoPath, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	projects := [][]gpath.GotoPath{}
+	for _, file := range FindProjectFiles(cwd) {
+		gpaths, err := gpath.LoadProjectFile(file)
+		if err != nil {
+			if !skippedProjectFiles[file] {
+				skippedProjectFiles[file] = true
+				fmt.Fprintf(os.Stderr, "Warning: %v, it is skipped\n", err)
+			}
+			continue
+		}
+		projects = append(projects, gpaths)
+	}
+
+	return projects, nil
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"fmt"
+	"goto/src/gpath"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// Environment variable with the id of the shell session, the shell integration passes the PID of the shell.
+// Each session has its own directory stack.
+const SESSION_ENV_VAR = "GOTO_SESSION"
+
+// Valid ids of the sessions
+var sessionRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// GetSession returns the id of the shell session: the GOTO_SESSION environment variable or,
+// if it is not set, the PID of the parent process
+func GetSession() (string, error) {
+	session := os.Getenv(SESSION_ENV_VAR)
+	if session == "" {
+		return strconv.Itoa(os.Getppid()), nil
+	}
+
+	if !sessionRegexp.MatchString(session) {
+		return "", fmt.Errorf("the session id \"%s\" is invalid (only letters, numbers, \"-\" and \"_\" are allowed)", session)
+	}
+	return session, nil
+}
+
+// Return the path of the directory stack file of the session (in the directory of the temporal gpaths file)
+func GetStackFilePath() (string, error) {
+	session, err := GetSession()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(tempGotoPathsFile), "stack-"+session+".json"), nil
+}
+
+// Load the directory stack of the session
+func LoadStack() (gpath.DirStack, error) {
+	file, err := GetStackFilePath()
+	if err != nil {
+		return gpath.DirStack{}, err
+	}
+	return gpath.LoadStackFile(file)
+}
+
+// Overwrite the directory stack of the session
+func UpdateStack(stack gpath.DirStack) error {
+	file, err := GetStackFilePath()
+	if err != nil {
+		return err
+	}
+	return gpath.SaveStackFile(stack, file)
+}
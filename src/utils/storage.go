@@ -1,13 +1,18 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"goto/src/config"
 	"goto/src/gpath"
 	"log"
 	"os"
 	"os/user"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 var (
@@ -25,6 +30,9 @@ var (
 
 	//Path of backup the gpaths file
 	gotoPathsFileBackup string
+
+	//Path of the navigation history file
+	historyFile string
 )
 
 const (
@@ -34,6 +42,9 @@ const (
 	// Name of the goto paths file.
 	GOTO_FILE_NAME = "goto-paths.json"
 
+	// Name of the navigation history file.
+	HISTORY_FILE_NAME = "goto-history.json"
+
 	// This environment variable is used to indicate that the
 	// application is running in a testing context. Using this variable
 	// allows the application to adjust its behavior accordingly,
@@ -68,10 +79,19 @@ func SetupConfigFile() {
 		configDir = filepath.Join(configDir, TESTING_FILE_DIR)
 	}
 
+	// Load the settings of the config file (e.g., ~/.config/goto/config.json) before creating the files,
+	// the error is reported by the root command (see config.Check)
+	_ = config.Setup(configDir)
+
 	// Define the paths for the gpaths file and its backup (e.g., ~/.config/goto/goto-paths.json and ~/.config/goto/goto-paths.json.backup)
+	// The default profile is used until other is selected with UseProfile
+	currentProfile = DEFAULT_PROFILE
 	gotoPathsFile = filepath.Join(configDir, GOTO_FILE_NAME)
 	gotoPathsFileBackup = filepath.Clean(gotoPathsFile + ".backup")
 
+	// Define the path for the navigation history (e.g., ~/.config/goto/goto-history.json)
+	historyFile = filepath.Join(configDir, HISTORY_FILE_NAME)
+
 	if err := gpath.CreateGotoPathsFile(gotoPathsFile); err != nil {
 		log.Fatalf("Failed to create goto-paths file: %v", err)
 	}
@@ -143,18 +163,50 @@ func createAndVerifySecureDir(dir string) (string, error) {
 	return filepath.Join(dir, GOTO_FILE_NAME), nil
 }
 
+// Return the path of the journal of the gpaths file (or the temporal gpath file if the flag passed)
+func GetJournalFilePath(useTemporal bool) string {
+	return GetFilePath(useTemporal) + ".journal"
+}
+
+// Load the journal of the gpaths file (or the temporal gpath file if the flag passed)
+func LoadJournal(useTemporal bool) (gpath.Journal, error) {
+	return gpath.LoadJournalFile(GetJournalFilePath(useTemporal))
+}
+
+// Overwrite the journal of the gpaths file (or the temporal gpath file if the flag passed)
+func UpdateJournal(useTemporal bool, journal gpath.Journal) error {
+	return gpath.SaveJournalFile(journal, GetJournalFilePath(useTemporal))
+}
+
 // Overwrite the gpaths file (or the temporal gpath file if the flag passed) with the gpaths array.
+// The previous goto-paths file is kept as an automatic backup (see SnapshotGPaths).
 func UpdateGPaths(useTemporal bool, gpaths []gpath.GotoPath) error {
 	if useTemporal {
+		//The gpaths of other sessions are kept (they aren't loaded) and the expired ones are removed
+		hidden, err := hiddenTemporalGPaths()
+		if err != nil {
+			return err
+		}
+
 		//If the array is valid, apply the changes
-		return gpath.SaveGPathsFile(gpaths, tempGotoPathsFile)
+		return gpath.SaveGPathsFile(append(slices.Clone(gpaths), hidden...), tempGotoPathsFile)
 	} else {
+		//Keep the current file as an automatic backup before changing it
+		if err := gpath.CheckRepeatedItems(gpaths); err != nil {
+			return err
+		}
+		if err := SnapshotGPaths(); err != nil {
+			return fmt.Errorf("can't save the automatic backup: %v", err)
+		}
+
 		//If the array is valid, apply the changes
 		return gpath.SaveGPathsFile(gpaths, gotoPathsFile)
 	}
 }
 
 // Load the gpaths file (or the temporal gpath file if the flag passed) in the gpaths array.
+// If the file is corrupted, the error explains how to recover the last valid copy.
+// From the temporal file, only the gpaths of the session that haven't expired are loaded.
 func LoadGPaths(useTemporal bool) ([]gpath.GotoPath, error) {
 	gpaths := &[]gpath.GotoPath{}
 	var err error
@@ -163,7 +215,80 @@ func LoadGPaths(useTemporal bool) ([]gpath.GotoPath, error) {
 	} else {
 		err = gpath.LoadGPathsFile(gpaths, gotoPathsFile)
 	}
-	return *gpaths, err
+	if err != nil {
+		return *gpaths, withRestoreHint(err, useTemporal)
+	}
+
+	if useTemporal {
+		return visibleTemporalGPaths(*gpaths)
+	}
+	return *gpaths, nil
+}
+
+// ReadGPaths loads the gpaths file (or the temporal gpath file if the flag passed) without checking
+// the repeated items, to report them (e.g. in valid-paths).
+func ReadGPaths(useTemporal bool) ([]gpath.GotoPath, error) {
+	gpaths, err := gpath.ReadGPathsFile(GetFilePath(useTemporal))
+	if err != nil {
+		return gpaths, withRestoreHint(err, useTemporal)
+	}
+
+	if useTemporal {
+		return visibleTemporalGPaths(gpaths)
+	}
+	return gpaths, nil
+}
+
+// visibleTemporalGPaths returns the temporal gpaths of the session that haven't expired
+func visibleTemporalGPaths(gpaths []gpath.GotoPath) ([]gpath.GotoPath, error) {
+	session, err := GetSession()
+	if err != nil {
+		return nil, err
+	}
+
+	visible, _ := gpath.SplitTemporalGPaths(gpaths, session, time.Now())
+	return visible, nil
+}
+
+// hiddenTemporalGPaths returns the temporal gpaths of other sessions that haven't expired
+func hiddenTemporalGPaths() ([]gpath.GotoPath, error) {
+	session, err := GetSession()
+	if err != nil {
+		return nil, err
+	}
+
+	// If the file doesn't exist or can't be parsed, there isn't anything to keep
+	gpaths, err := gpath.ReadGPathsFile(tempGotoPathsFile)
+	if err != nil {
+		return nil, nil
+	}
+
+	_, hidden := gpath.SplitTemporalGPaths(gpaths, session, time.Now())
+	return hidden, nil
+}
+
+// withRestoreHint adds to a CorruptedFileError how to restore the last valid copy of the file
+func withRestoreHint(err error, useTemporal bool) error {
+	var corrupted *gpath.CorruptedFileError
+	if errors.As(err, &corrupted) {
+		restoreCmd := "goto restore --last-good"
+		if useTemporal {
+			restoreCmd += " -t"
+		}
+		err = fmt.Errorf("%w (run \"%s\" to recover it)", err, restoreCmd)
+	}
+	return err
+}
+
+// Take the lock of the gpaths file (or the temporal gpath file if the flag passed), it must be taken
+// during a load-modify-save cycle to serialise concurrent invocations. The returned function releases it.
+func LockGPaths(useTemporal bool) (func(), error) {
+	return gpath.LockFile(GetFilePath(useTemporal))
+}
+
+// Return the path of the last valid copy of the GPaths File (temporal and normal)
+func GetLastGoodFilePath(useTemporal bool) string {
+	return gpath.LastGoodCopyPath(GetFilePath(useTemporal))
 }
 
 // Return the path of the GPaths File (temporal and normal)
@@ -175,9 +300,31 @@ func GetFilePath(useTemporal bool) string {
 	}
 }
 
-// Return the default path of the GPaths File
+// Load the navigation history file in the history array.
+func LoadHistory() ([]gpath.HistoryEntry, error) {
+	history := &[]gpath.HistoryEntry{}
+	err := gpath.LoadHistoryFile(history, historyFile)
+	return *history, err
+}
+
+// Overwrite the navigation history file with the history array.
+func UpdateHistory(history []gpath.HistoryEntry) error {
+	return gpath.SaveHistoryFile(history, historyFile)
+}
+
+// Return the default path of the backup of the GPaths File: the backup_file setting (with "{profile}"
+// replaced by the profile in use) or, if it is not set, the GPaths File with the ".backup" extension
 func GetDefaultBackupFilePath() string {
-	return gotoPathsFileBackup
+	backupFile := config.Get().BackupFile
+	if backupFile == "" {
+		return gotoPathsFileBackup
+	}
+
+	backupFile = gpath.ExpandPath(strings.ReplaceAll(backupFile, "{profile}", currentProfile))
+	if !filepath.IsAbs(backupFile) {
+		backupFile = filepath.Join(configDir, backupFile)
+	}
+	return filepath.Clean(backupFile)
 }
 
 // GetConfigDir returns the configuration directory path
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSaveGPathsFile_Atomic(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "goto-paths.json")
+
+	first := []gpath.GotoPath{{Path: "/a", Abbreviation: "a"}}
+	second := []gpath.GotoPath{{Path: "/b", Abbreviation: "b"}}
+
+	if err := gpath.SaveGPathsFile(first, file); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new file doesn't have a last valid copy
+	if _, err := os.Stat(gpath.LastGoodCopyPath(file)); !os.IsNotExist(err) {
+		t.Errorf("Expected no last valid copy of a new file")
+	}
+	if err := gpath.SaveGPathsFile(second, file); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the file and its last valid copy must remain (no temporal files)
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("Expected the file and its last valid copy, got %v", entries)
+	}
+
+	var lastGood []gpath.GotoPath
+	if err := gpath.LoadGPathsFile(&lastGood, gpath.LastGoodCopyPath(file)); err != nil {
+		t.Fatalf("Failed to load the last valid copy: %v", err)
+	}
+	if len(lastGood) != 1 || lastGood[0].Abbreviation != "b" {
+		t.Errorf("Expected the saved version as the last valid copy, got %v", lastGood)
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected 0600 permissions, got %v", info.Mode().Perm())
+	}
+}
+
+func TestLoadGPathsFile_Corrupted(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "goto-paths.json")
+
+	// Without a last valid copy
+	os.WriteFile(file, []byte(`[{"path": "/a", "abbrev`), 0600)
+	var gpaths []gpath.GotoPath
+	err := gpath.LoadGPathsFile(&gpaths, file)
+	var corrupted *gpath.CorruptedFileError
+	if err == nil || errors.As(err, &corrupted) {
+		t.Errorf("Expected a plain parse error, got %v", err)
+	}
+
+	// With a last valid copy
+	if err := gpath.SaveGPathsFile([]gpath.GotoPath{{Path: "/a", Abbreviation: "a"}}, gpath.LastGoodCopyPath(file)); err != nil {
+		t.Fatal(err)
+	}
+	err = gpath.LoadGPathsFile(&gpaths, file)
+	if !errors.As(err, &corrupted) {
+		t.Fatalf("Expected CorruptedFileError, got %v", err)
+	}
+	if corrupted.LastGood != gpath.LastGoodCopyPath(file) {
+		t.Errorf("Unexpected last valid copy %s", corrupted.LastGood)
+	}
+}
+
+func TestRecoverLastGoodCopy(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	if err := core.AddPath(".", "p1", false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Corrupt the file
+	if err := os.WriteFile(utils.GetFilePath(false), []byte("[{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := utils.LoadGPaths(false)
+	if err == nil || !strings.Contains(err.Error(), "goto restore --last-good") {
+		t.Fatalf("Expected an error with the recovery command, got %v", err)
+	}
+
+	if err := core.RestoreGPaths(utils.GetLastGoodFilePath(false), false); err != nil {
+		t.Fatalf("Failed to restore the last valid copy: %v", err)
+	}
+
+	// The last change is not lost
+	gpaths, err := utils.LoadGPaths(false)
+	if err != nil {
+		t.Fatalf("Expected the file to be recovered, got %v", err)
+	}
+	if _, ok := gpath.GetPathFromIndexOrAbbreviation(gpaths, "p1"); !ok {
+		t.Errorf("Expected the last added path in the recovered file, got %v", gpaths)
+	}
+}
+
+func TestAddPath_Concurrent(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	before, err := utils.LoadGPaths(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := t.TempDir()
+	const n = 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		dir := filepath.Join(base, fmt.Sprint(i))
+		mkdirs(t, dir)
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- core.AddPath(dir, fmt.Sprintf("c%d", i), false)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Concurrent add failed: %v", err)
+		}
+	}
+
+	after, err := utils.LoadGPaths(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != len(before)+n {
+		t.Errorf("Expected %d gpaths after the concurrent adds, got %d", len(before)+n, len(after))
+	}
+}
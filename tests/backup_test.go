@@ -2,9 +2,13 @@ package tests
 
 import (
 	"goto/src/core"
+	"goto/src/utils"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/bytedance/sonic"
 )
 
 func TestBackup(t *testing.T) {
@@ -46,3 +50,62 @@ func TestBackup_OutputExists(t *testing.T) {
 		t.Error("Expected error when backup file exists")
 	}
 }
+
+func TestBackup_LegacyOutputPath(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	// Before the global output flag, -o/--output was the destination path
+	backupFile := filepath.Join(t.TempDir(), "legacy.json")
+	var err error
+	stderr := captureStderr(func() {
+		captureOutput(func() { err = executeCommand(t, "backup", "-o", backupFile) })
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(backupFile); err != nil {
+		t.Errorf("Backup file not created: %v", err)
+	}
+	if !strings.Contains(stderr, "deprecated") {
+		t.Errorf("Expected the deprecation warning, got %q", stderr)
+	}
+}
+
+func TestBackup_ListOutput(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	if err := core.AddPath(t.TempDir(), "bkp", false); err != nil {
+		t.Fatal(err)
+	}
+	generations, err := core.ListBackupGenerations()
+	if err != nil || len(generations) != 1 {
+		t.Fatalf("Expected 1 generation, got %v (err: %v)", generations, err)
+	}
+
+	out := captureOutput(func() {
+		if err := executeCommand(t, "backup", "list", "-o", "json"); err != nil {
+			t.Error(err)
+		}
+	})
+
+	var entries []utils.OutputEntry
+	if err := sonic.ConfigFastest.UnmarshalFromString(out, &entries); err != nil {
+		t.Fatalf("Invalid JSON output %q: %v", out, err)
+	}
+	if len(entries) != 1 || entries[0].Path != generations[0].File || entries[0].SavedAt == "" {
+		t.Errorf("Expected the generation %v, got %+v", generations[0], entries)
+	}
+
+	// The templates work like in the other lists
+	out = captureOutput(func() {
+		if err := executeCommand(t, "backup", "list", "-o", "{{.Index}} {{.Path}}"); err != nil {
+			t.Error(err)
+		}
+	})
+	if out != "0 "+generations[0].File+"\n" {
+		t.Errorf("Unexpected template output %q", out)
+	}
+}
@@ -0,0 +1,219 @@
+package tests
+
+import (
+	"encoding/json"
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// Helper to write the goto-paths file without validating it
+func writeGPathsFile(t *testing.T, gpaths []gpath.GotoPath) {
+	t.Helper()
+	data, err := json.Marshal(gpaths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(utils.GetFilePath(false), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Helper to get the kinds of the problems by index
+func problemKinds(problems []gpath.Problem) map[int][]string {
+	kinds := map[int][]string{}
+	for _, p := range problems {
+		kinds[p.Index] = append(kinds[p.Index], p.Kind)
+	}
+	return kinds
+}
+
+func TestCheckPaths_AllProblems(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	gpaths := []gpath.GotoPath{
+		{Path: dir, Abbreviation: "ok"},
+		{Path: filepath.Join(dir, "missing"), Abbreviation: "missing"},
+		{Path: file, Abbreviation: "file"},
+		{Path: dir, Abbreviation: "again"},
+		{Path: filepath.Join(dir, "missing2"), Abbreviation: "ok"},
+		{Path: "relative", Abbreviation: "123"},
+	}
+
+	if runtime.GOOS != "windows" {
+		loop := filepath.Join(dir, "loop")
+		if err := os.Symlink(loop, loop); err != nil {
+			t.Fatal(err)
+		}
+		gpaths = append(gpaths, gpath.GotoPath{Path: loop, Abbreviation: "loop"})
+	}
+	writeGPathsFile(t, gpaths)
+
+	// All the problems are reported, not only the first one
+	_, problems, err := core.CheckPaths(false)
+	if err != nil {
+		t.Fatalf("CheckPaths failed: %v", err)
+	}
+
+	kinds := problemKinds(problems)
+	expected := map[int][]string{
+		1: {gpath.ProblemMissing},
+		2: {gpath.ProblemNotDirectory},
+		3: {gpath.ProblemDuplicatePath},
+		4: {gpath.ProblemMissing, gpath.ProblemDuplicateAbbv},
+		5: {gpath.ProblemInvalidPath, gpath.ProblemInvalidAbbreviation},
+	}
+	if runtime.GOOS != "windows" {
+		expected[6] = []string{gpath.ProblemSymlinkLoop}
+	}
+
+	for i, want := range expected {
+		if len(kinds[i]) != len(want) {
+			t.Errorf("Index %v: expected %v, got %v", i, want, kinds[i])
+			continue
+		}
+		for j := range want {
+			if kinds[i][j] != want[j] {
+				t.Errorf("Index %v: expected %v, got %v", i, want, kinds[i])
+			}
+		}
+	}
+	if len(kinds[0]) != 0 {
+		t.Errorf("Expected the first gpath to be valid, got %v", kinds[0])
+	}
+}
+
+func TestFixPaths_Delete(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	dir := t.TempDir()
+	writeGPathsFile(t, []gpath.GotoPath{
+		{Path: dir, Abbreviation: "ok"},
+		{Path: filepath.Join(dir, "missing"), Abbreviation: "missing"},
+		{Path: dir, Abbreviation: "again"},
+	})
+
+	fixes, remaining, err := core.FixPaths(core.FixDelete, nil, false)
+	if err != nil {
+		t.Fatalf("FixPaths failed: %v", err)
+	}
+	if len(fixes) != 2 || len(remaining) != 0 {
+		t.Errorf("Expected 2 fixes and no problems, got %v and %v", fixes, remaining)
+	}
+
+	gpaths, _ := utils.LoadGPaths(false)
+	if len(gpaths) != 1 || gpaths[0].Abbreviation != "ok" {
+		t.Errorf("Unexpected gpaths after the fix: %v", gpaths)
+	}
+
+	// The file before the fix had repeated paths, it can't be restored with undo
+	if _, err := core.Undo(false); err == nil {
+		t.Error("Expected error undoing a fix of a file with repeated paths")
+	}
+
+	if _, _, err := core.FixPaths("other", nil, false); err == nil {
+		t.Error("Expected error for an invalid fix mode")
+	}
+}
+
+func TestFixPaths_Relocate(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	root := t.TempDir()
+	moved := filepath.Join(root, "work", "billing-api")
+	mkdirs(t, moved, filepath.Join(root, "a", "docs"), filepath.Join(root, "b", "docs"))
+
+	writeGPathsFile(t, []gpath.GotoPath{
+		{Path: root, Abbreviation: "root"},
+		{Path: "/old/place/billing-api", Abbreviation: "api"},
+		{Path: "/old/place/docs", Abbreviation: "docs"},
+		{Path: moved, Abbreviation: "api"},
+	})
+
+	fixes, remaining, err := core.FixPaths(core.FixRelocate, []string{root}, false)
+	if err != nil {
+		t.Fatalf("FixPaths failed: %v", err)
+	}
+
+	// "billing-api" is already a gpath, "docs" is ambiguous and the repeated abbreviation is renamed
+	if len(fixes) != 1 || fixes[0].Problem.Kind != gpath.ProblemDuplicateAbbv {
+		t.Errorf("Unexpected fixes: %v", fixes)
+	}
+	if len(remaining) != 2 {
+		t.Errorf("Expected 2 problems, got %v", remaining)
+	}
+
+	// Without the repeated path, the missing path is relocated
+	writeGPathsFile(t, []gpath.GotoPath{
+		{Path: root, Abbreviation: "root"},
+		{Path: "/old/place/billing-api", Abbreviation: "api"},
+	})
+	fixes, remaining, err = core.FixPaths(core.FixRelocate, []string{root}, false)
+	if err != nil || len(fixes) != 1 || len(remaining) != 0 {
+		t.Fatalf("Expected the path relocated, got %v and %v (err: %v)", fixes, remaining, err)
+	}
+	if gpaths, _ := utils.LoadGPaths(false); gpaths[1].Path != moved {
+		t.Errorf("Expected %s, got %v", moved, gpaths)
+	}
+}
+
+func TestFixPaths_HomeRelative(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	home := t.TempDir()
+	mkdirs(t, filepath.Join(home, "projects"))
+	t.Setenv("HOME", home)
+
+	writeGPathsFile(t, []gpath.GotoPath{
+		{Path: home, Abbreviation: "h"},
+		{Path: "/home/goto-old-user/projects", Abbreviation: "p"},
+	})
+
+	fixes, remaining, err := core.FixPaths(core.FixHomeRelative, nil, false)
+	if err != nil || len(fixes) != 1 || len(remaining) != 0 {
+		t.Fatalf("Expected the path moved to the home, got %v and %v (err: %v)", fixes, remaining, err)
+	}
+
+	// The path is stored relative to the home
+	data, _ := os.ReadFile(utils.GetFilePath(false))
+	if !strings.Contains(string(data), `"~/projects"`) {
+		t.Errorf("Expected the path ~/projects in the file, got %s", data)
+	}
+	if gpaths, _ := utils.LoadGPaths(false); gpaths[1].ExpandedPath() != filepath.Join(home, "projects") {
+		t.Errorf("Unexpected gpaths after the fix: %v", gpaths)
+	}
+}
+
+func TestRebaseOnHome(t *testing.T) {
+	tests := []struct {
+		path, home, want string
+		ok               bool
+	}{
+		{"/home/old/projects/api", "/home/me", "/home/me/projects/api", true},
+		{"/Users/old/projects", "/home/me", "/home/me/projects", true},
+		{"/home/me/projects", "/home/me", "", false},
+		{"/opt/projects", "/home/me", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := gpath.RebaseOnHome(tt.path, tt.home)
+		if ok != tt.ok || got != filepath.FromSlash(tt.want) {
+			t.Errorf("RebaseOnHome(%s, %s) = %s, %v; expected %s, %v", tt.path, tt.home, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"bytes"
+	"goto/src/cmd"
 	"goto/src/utils"
 	"io"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // Helper to reset the temporal file before each test
@@ -75,3 +77,50 @@ func RunExpectedExit(t *testing.T, testName string, envKey string) {
 	}
 	t.Fatalf("process ran successfully (err: %v), but expected exit status 1", err)
 }
+
+// Helper to create directories (and their parents) for a test
+func mkdirs(t *testing.T, dirs ...string) {
+	t.Helper()
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory %s: %v", dir, err)
+		}
+	}
+}
+
+// Helper to capture stderr
+func captureStderr(f func()) string {
+	old := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	f()
+
+	w.Close()
+	os.Stderr = old
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return buf.String()
+}
+
+// Helper to run the goto command with the args (as in the command line) and restore the default value of
+// the flags after it, cobra keeps the values of the flags between executions
+func executeCommand(t *testing.T, args ...string) error {
+	c, _, err := cmd.RootCmd.Find(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		c.Flags().VisitAll(func(f *pflag.Flag) {
+			if f.Changed {
+				_ = f.Value.Set(f.DefValue)
+				f.Changed = false
+			}
+		})
+	}()
+
+	cmd.RootCmd.SetArgs(args)
+	defer cmd.RootCmd.SetArgs(nil)
+	return cmd.RootCmd.Execute()
+}
@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"goto/src/utils"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	if diff := utils.UnifiedDiff("a", "b", "x\ny\n", "x\ny\n"); diff != "" {
+		t.Errorf("expected an empty diff for equal texts, got:\n%s", diff)
+	}
+
+	oldText := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	newText := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+
+	expected := `--- old
++++ new
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+`
+	if diff := utils.UnifiedDiff("old", "new", oldText, newText); diff != expected {
+		t.Errorf("unexpected diff:\n%s\nexpected:\n%s", diff, expected)
+	}
+
+	// Adding to an empty file
+	expected = "--- /dev/null\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n"
+	if diff := utils.UnifiedDiff("/dev/null", "new", "", "a\nb\n"); diff != expected {
+		t.Errorf("unexpected diff:\n%s\nexpected:\n%s", diff, expected)
+	}
+}
@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"bytes"
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExport_Formats(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	dir := t.TempDir()
+	quoted := filepath.Join(dir, "it's")
+	mkdirs(t, quoted)
+
+	gpaths := []gpath.GotoPath{
+		{Path: quoted, Abbreviation: "zz"},
+		{Path: dir, Abbreviation: "my-dir"},
+	}
+	if err := utils.UpdateGPaths(false, gpaths); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"cdpath":    "export CDPATH='.:" + dir + ":" + dir + "/it'\\''s'\n",
+		"vars":      "my_dir='" + dir + "'\nzz='" + dir + "/it'\\''s'\n",
+		"zsh":       "hash -d my_dir='" + dir + "'\nhash -d zz='" + dir + "/it'\\''s'\n",
+		"bashmarks": "export DIR_my_dir='" + dir + "'\nexport DIR_zz='" + dir + "/it'\\''s'\n",
+		"zoxide":    dir + "|1|0\n" + dir + "/it's|1|0\n",
+	}
+
+	for format, want := range expected {
+		var sb strings.Builder
+		if err := core.ExportGPaths(&sb, format, false); err != nil {
+			t.Errorf("%s: ExportGPaths failed: %v", format, err)
+			continue
+		}
+		if sb.String() != want {
+			t.Errorf("%s: expected\n%s\ngot\n%s", format, want, sb.String())
+		}
+	}
+
+	// The zoxide export uses the time of the last visit of the history
+	if err := utils.UpdateHistory(gpath.RecordVisit(nil, dir, time.Unix(1700000000, 0))); err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := core.ExportGPaths(&sb, "zoxide", false); err != nil || !strings.HasPrefix(sb.String(), dir+"|1|1700000000\n") {
+		t.Errorf("Expected the time of the visit in the zoxide export, got %s (err: %v)", sb.String(), err)
+	}
+	_ = utils.UpdateHistory([]gpath.HistoryEntry{})
+
+	sb.Reset()
+	if err := core.ExportGPaths(&sb, "fish", false); err != nil || !strings.HasPrefix(sb.String(), "abbr -a -- 'my-dir' ") {
+		t.Errorf("Unexpected fish export: %s (err: %v)", sb.String(), err)
+	}
+
+	if err := core.ExportGPaths(&sb, "unknown", false); err == nil {
+		t.Error("Expected error for an unknown format")
+	}
+
+	// Two abbreviations with the same variable name can't be exported as variables
+	gpaths = append(gpaths, gpath.GotoPath{Path: filepath.Dir(dir), Abbreviation: "my_dir"})
+	if err := utils.UpdateGPaths(false, gpaths); err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{"vars", "zsh", "bashmarks"} {
+		if err := core.ExportGPaths(&sb, format, false); err == nil || !strings.Contains(err.Error(), "my_dir") {
+			t.Errorf("%s: expected an error for the variable name my_dir, got %v", format, err)
+		}
+	}
+}
+
+func TestExport_FileIsDeterministic(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	for _, format := range core.ExporterNames() {
+		file := filepath.Join(t.TempDir(), format)
+		if err := core.ExportGPathsToFile(file, format, false); err != nil {
+			t.Fatalf("%s: ExportGPathsToFile failed: %v", format, err)
+		}
+		first, _ := os.ReadFile(file)
+
+		if err := core.ExportGPathsToFile(file, format, false); err != nil {
+			t.Fatalf("%s: ExportGPathsToFile failed: %v", format, err)
+		}
+		second, _ := os.ReadFile(file)
+
+		if len(first) == 0 || !bytes.Equal(first, second) {
+			t.Errorf("%s: Expected the same export twice, got\n%s\nand\n%s", format, first, second)
+		}
+	}
+
+	file := filepath.Join(t.TempDir(), "sdirs")
+	if err := core.ExportGPathsToFile(file, "bashmarks", false); err != nil {
+		t.Fatalf("ExportGPathsToFile failed: %v", err)
+	}
+
+	// The export can be imported back
+	plan, err := core.PlanImport("bashmarks", file, false)
+	if err != nil || len(plan.Added) != 0 {
+		t.Errorf("Expected all the exported paths to exist already, got %v (err: %v)", plan.Added, err)
+	}
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"errors"
+	"goto/src/core"
+	"goto/src/gpath"
+	"path/filepath"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	gpaths := []gpath.GotoPath{
+		{Path: "/home/me/projects/billing-api", Abbreviation: "bapi"},
+		{Path: "/home/me/projects/billing-web", Abbreviation: "bweb"},
+		{Path: "/home/me/Documents", Abbreviation: "docs"},
+	}
+
+	tests := []struct {
+		name      string
+		terms     []string
+		wantIndex int
+		ambiguous bool
+	}{
+		{"abbreviation prefix", []string{"doc"}, 2, false},
+		{"basename substring", []string{"web"}, 1, false},
+		{"basename subsequence", []string{"dcmnts"}, 2, false},
+		{"path segments", []string{"proj", "api"}, 0, false},
+		{"case insensitive", []string{"DOCUMENTS"}, 2, false},
+		{"ambiguous", []string{"billing"}, -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := gpath.FuzzyMatch(gpaths, tt.terms)
+			if len(matches) == 0 {
+				t.Fatal("Expected at least one match")
+			}
+
+			if gpath.IsAmbiguous(matches) != tt.ambiguous {
+				t.Fatalf("IsAmbiguous() = %v, want %v (%v)", !tt.ambiguous, tt.ambiguous, matches)
+			}
+
+			if !tt.ambiguous && matches[0].Index != tt.wantIndex {
+				t.Errorf("Best match index = %d, want %d (%v)", matches[0].Index, tt.wantIndex, matches)
+			}
+		})
+	}
+
+	if matches := gpath.FuzzyMatch(gpaths, []string{"xyz"}); len(matches) != 0 {
+		t.Errorf("Expected no matches, got %v", matches)
+	}
+
+	if matches := gpath.FuzzyMatch(gpaths, []string{"  "}); len(matches) != 0 {
+		t.Errorf("Expected no matches for blank terms, got %v", matches)
+	}
+}
+
+func TestResolvePath_Fuzzy(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	base := t.TempDir()
+	api := filepath.Join(base, "projects", "billing-api")
+	web := filepath.Join(base, "projects", "billing-web")
+	mkdirs(t, api, web)
+
+	if err := core.AddPath(api, "bapi", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.AddPath(web, "bweb", false); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := core.ResolvePath([]string{"proj", "api"}, false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != api {
+		t.Errorf("got %q, want %q", got, api)
+	}
+
+	_, err = core.ResolvePath([]string{"billing"}, false, false)
+	var ambiguous *core.AmbiguousMatchError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Expected AmbiguousMatchError, got %v", err)
+	}
+	if len(ambiguous.Matches) != 2 {
+		t.Errorf("Expected 2 candidates, got %d", len(ambiguous.Matches))
+	}
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGenerations_SnapshotOnChange(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	dir := t.TempDir()
+	mkdirs(t, filepath.Join(dir, "a"), filepath.Join(dir, "b"))
+	before, _ := utils.LoadGPaths(false)
+
+	if err := core.AddPath(filepath.Join(dir, "a"), "a", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.AddPath(filepath.Join(dir, "b"), "b", false); err != nil {
+		t.Fatal(err)
+	}
+
+	generations, err := core.ListBackupGenerations()
+	if err != nil || len(generations) != 2 {
+		t.Fatalf("Expected 2 generations, got %v (err: %v)", generations, err)
+	}
+
+	// The newest generation is the state before the last change
+	gpaths, err := core.LoadBackupGeneration(0)
+	if err != nil || len(gpaths) != len(before)+1 {
+		t.Errorf("Expected %d gpaths in the generation 0, got %v (err: %v)", len(before)+1, gpaths, err)
+	}
+
+	oldest, err := core.LoadBackupGeneration(1)
+	if err != nil || !gpath.DiffGPaths(before, oldest).Empty() {
+		t.Errorf("Expected the initial gpaths in the generation 1, got %v (err: %v)", oldest, err)
+	}
+
+	if _, err := core.LoadBackupGeneration(2); err == nil {
+		t.Error("Expected error for a generation that doesn't exist")
+	}
+
+	// Restore the oldest generation
+	file, _ := utils.GetGenerationFilePath(1)
+	if err := core.RestoreGPaths(file, false); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := utils.LoadGPaths(false); !gpath.DiffGPaths(before, after).Empty() {
+		t.Errorf("Expected the initial gpaths after the restore, got %v", after)
+	}
+
+	// The temporal file doesn't have automatic backups
+	if err := core.AddPath(filepath.Join(dir, "a"), "a", true); err != nil {
+		t.Fatal(err)
+	}
+	if generations, _ := core.ListBackupGenerations(); len(generations) != 3 {
+		t.Errorf("Expected 3 generations, got %d", len(generations))
+	}
+}
+
+func TestGenerations_Retention(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	useConfigDir(t, `{"backup_generations": 2}`)
+
+	dir := t.TempDir()
+	for _, name := range []string{"a", "b", "c", "d"} {
+		mkdirs(t, filepath.Join(dir, name))
+		if err := core.AddPath(filepath.Join(dir, name), name, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	generations, _ := core.ListBackupGenerations()
+	if len(generations) != 2 {
+		t.Errorf("Expected 2 generations, got %d", len(generations))
+	}
+
+	// An unchanged file doesn't create a new generation
+	backups := t.TempDir()
+	file := utils.GetFilePath(false)
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := gpath.SnapshotFile(file, backups, "test", 5, now.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if generations, _ := gpath.ListGenerations(backups, "test"); len(generations) != 1 {
+		t.Errorf("Expected 1 generation of an unchanged file, got %d", len(generations))
+	}
+
+	t.Setenv("GOTO_BACKUP_GENERATIONS", "0")
+	mkdirs(t, filepath.Join(dir, "e"))
+	if err := core.AddPath(filepath.Join(dir, "e"), "e", false); err != nil {
+		t.Fatal(err)
+	}
+	if generations, _ := core.ListBackupGenerations(); len(generations) != 2 {
+		t.Errorf("Expected no new generations when they are disabled, got %d", len(generations))
+	}
+}
+
+func TestGenerations_SkipCorrupted(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "goto-paths.json")
+	backups := filepath.Join(dir, "backups")
+
+	// A file that can't be parsed is not kept as a generation
+	if err := os.WriteFile(file, []byte(`{"version": 1, "entries": [`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := gpath.SnapshotFile(file, backups, "test", 5, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if generations, _ := gpath.ListGenerations(backups, "test"); len(generations) != 0 {
+		t.Errorf("Expected no generations of a corrupted file, got %v", generations)
+	}
+
+	if err := gpath.SaveGPathsFile([]gpath.GotoPath{{Path: dir, Abbreviation: "d"}}, file); err != nil {
+		t.Fatal(err)
+	}
+	if err := gpath.SnapshotFile(file, backups, "test", 5, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if generations, _ := gpath.ListGenerations(backups, "test"); len(generations) != 1 {
+		t.Errorf("Expected 1 generation, got %v", generations)
+	}
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFrecency(t *testing.T) {
+	now := time.Now()
+
+	recent := gpath.HistoryEntry{Path: "/a", Count: 2, LastVisit: now.Unix()}
+	old := gpath.HistoryEntry{Path: "/b", Count: 2, LastVisit: now.Add(-30 * 24 * time.Hour).Unix()}
+
+	if recent.Frecency(now) <= old.Frecency(now) {
+		t.Errorf("Expected recent entry to have a better score (%v <= %v)", recent.Frecency(now), old.Frecency(now))
+	}
+}
+
+func TestRecordVisit(t *testing.T) {
+	now := time.Now()
+
+	history := gpath.RecordVisit(nil, "/a", now)
+	history = gpath.RecordVisit(history, "/a", now)
+	history = gpath.RecordVisit(history, "/b", now)
+
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(history))
+	}
+	if history[0].Count != 2 {
+		t.Errorf("Expected count 2 for /a, got %v", history[0].Count)
+	}
+}
+
+func TestSaveHistoryFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "history.json")
+	history := gpath.RecordVisit(nil, "/a", time.Now())
+
+	// The file is replaced without leaving temporal files
+	for i := 0; i < 2; i++ {
+		if err := gpath.SaveHistoryFile(history, file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected only the history file, got %v", entries)
+	}
+
+	var loaded []gpath.HistoryEntry
+	if err := gpath.LoadHistoryFile(&loaded, file); err != nil || len(loaded) != 1 || loaded[0].Path != "/a" {
+		t.Errorf("Unexpected history %v (err: %v)", loaded, err)
+	}
+}
+
+func TestPruneHistory(t *testing.T) {
+	now := time.Now()
+
+	history := []gpath.HistoryEntry{
+		{Path: "/recent", Count: 5, LastVisit: now.Unix()},
+		{Path: "/old", Count: 5, LastVisit: now.Add(-gpath.HistoryMaxAge - time.Hour).Unix()},
+	}
+
+	history = gpath.PruneHistory(history, now)
+	if len(history) != 1 || history[0].Path != "/recent" {
+		t.Errorf("Expected only /recent to be kept, got %v", history)
+	}
+
+	// Aging
+	history = []gpath.HistoryEntry{
+		{Path: "/big", Count: gpath.HistoryMaxCount, LastVisit: now.Unix()},
+		{Path: "/small", Count: 1, LastVisit: now.Unix()},
+	}
+
+	history = gpath.PruneHistory(history, now)
+	if len(history) != 1 || history[0].Path != "/big" {
+		t.Fatalf("Expected only /big to be kept after aging, got %v", history)
+	}
+	if history[0].Count >= gpath.HistoryMaxCount {
+		t.Errorf("Expected count of /big to be aged, got %v", history[0].Count)
+	}
+}
+
+func TestBestFrecencyMatch(t *testing.T) {
+	now := time.Now()
+	base := t.TempDir()
+	api := filepath.Join(base, "projects", "billing-api")
+	web := filepath.Join(base, "projects", "billing-web")
+	mkdirs(t, api, web)
+
+	history := []gpath.HistoryEntry{
+		{Path: api, Count: 1, LastVisit: now.Unix()},
+		{Path: web, Count: 10, LastVisit: now.Unix()},
+		{Path: filepath.Join(base, "removed-billing"), Count: 100, LastVisit: now.Unix()},
+	}
+
+	if got, ok := gpath.BestFrecencyMatch(history, []string{"billing"}, now); !ok || got != web {
+		t.Errorf("Expected %s, got %s (found=%v)", web, got, ok)
+	}
+
+	if got, ok := gpath.BestFrecencyMatch(history, []string{"proj", "api"}, now); !ok || got != api {
+		t.Errorf("Expected %s, got %s (found=%v)", api, got, ok)
+	}
+
+	// The last keyword must match the base name
+	if _, ok := gpath.BestFrecencyMatch(history, []string{"projects"}, now); ok {
+		t.Error("Expected no match when the last keyword is not in the base name")
+	}
+}
+
+func TestResolvePath_FrecencyFallback(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	dir := filepath.Join(t.TempDir(), "frecency-target")
+	mkdirs(t, dir)
+
+	if err := core.RecordVisit(dir); err != nil {
+		t.Fatalf("RecordVisit failed: %v", err)
+	}
+
+	history, err := utils.LoadHistory()
+	if err != nil || len(history) != 1 {
+		t.Fatalf("Expected 1 history entry, got %v (err: %v)", history, err)
+	}
+
+	got, err := core.ResolvePath([]string{"frecency-tar"}, false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != dir {
+		t.Errorf("got %q, want %q", got, dir)
+	}
+
+	// The history is not used with the only directory flag
+	if _, err := core.ResolvePath([]string{"frecency-tar"}, true, false); err == nil {
+		t.Error("Expected error with the only directory flag")
+	}
+}
@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"fmt"
+	"goto/src/core"
+	"goto/src/utils"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Helper to write a file to import
+func writeImportFile(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "import")
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write the file to import: %v", err)
+	}
+	return file
+}
+
+func TestImport_Formats(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	dir := t.TempDir()
+	projects, api := filepath.Join(dir, "projects"), filepath.Join(dir, "api")
+	mkdirs(t, projects, api)
+
+	tests := []struct {
+		importer string
+		content  string
+	}{
+		{"zoxide", fmt.Sprintf("  20.5 %s\n   3.0 %s\n", projects, api)},
+		{"autojump", fmt.Sprintf("20.5\t%s\n3.0\t%s\n", projects, api)},
+		{"z", fmt.Sprintf("%s|20|1700000000\n%s|3|1700000000\n", projects, api)},
+		{"fasd", fmt.Sprintf("%s|20|1700000000\n%s|3|1700000000\n", projects, api)},
+		{"bashmarks", fmt.Sprintf("export DIR_projects=\"%s\"\nexport DIR_api=\"%s\"\n", projects, api)},
+	}
+
+	for _, tt := range tests {
+		plan, err := core.PlanImport(tt.importer, writeImportFile(t, tt.content), false)
+		if err != nil {
+			t.Errorf("%s: PlanImport failed: %v", tt.importer, err)
+			continue
+		}
+
+		if len(plan.Added) != 2 || plan.Added[0].Path != projects || plan.Added[0].Abbreviation != "projects" ||
+			plan.Added[1].Path != api || plan.Added[1].Abbreviation != "api" {
+			t.Errorf("%s: unexpected gpaths to import: %v", tt.importer, plan.Added)
+		}
+	}
+
+	if _, err := core.PlanImport("unknown", writeImportFile(t, ""), false); err == nil {
+		t.Error("Expected error for an unknown importer")
+	}
+
+	if _, err := core.PlanImport("z", writeImportFile(t, "no pipes here\n"), false); err == nil {
+		t.Error("Expected error for an invalid z file")
+	}
+}
+
+func TestImport_ConflictsAndApply(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	home, _ := os.UserHomeDir()
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "a", "docs"), filepath.Join(dir, "b", "docs")
+	mkdirs(t, first, second)
+
+	content := fmt.Sprintf("export DIR_h=\"%s\"\nexport DIR_h=\"%s\"\nexport DIR_x=\"%s\"\nexport DIR_y=\"%s\"\n",
+		home, first, second, filepath.Join(dir, "missing"))
+
+	plan, err := core.PlanImport("bashmarks", writeImportFile(t, content), false)
+	if err != nil {
+		t.Fatalf("PlanImport failed: %v", err)
+	}
+
+	// The home directory is already in the goto-paths file and the abbreviation "h" is renamed
+	if len(plan.Conflicts) != 2 {
+		t.Errorf("Expected 2 conflicts, got %v", plan.Conflicts)
+	}
+	if len(plan.Skipped) != 1 {
+		t.Errorf("Expected the missing path to be skipped, got %v", plan.Skipped)
+	}
+	if len(plan.Added) != 2 || plan.Added[0].Abbreviation != "docs" || plan.Added[1].Abbreviation != "x" {
+		t.Fatalf("Unexpected gpaths to import: %v", plan.Added)
+	}
+
+	// Nothing is written until the plan is applied
+	before, _ := utils.LoadGPaths(false)
+	if imported, err := core.ApplyImport(plan, false); err != nil || imported != 2 {
+		t.Fatalf("ApplyImport failed: %v (imported %d)", err, imported)
+	}
+	after, _ := utils.LoadGPaths(false)
+	if len(after) != len(before)+2 {
+		t.Errorf("Expected %d gpaths after the import, got %d", len(before)+2, len(after))
+	}
+
+	// Applying the plan again doesn't add anything
+	if imported, err := core.ApplyImport(plan, false); err != nil || imported != 0 {
+		t.Errorf("Expected nothing imported, got %d (err: %v)", imported, err)
+	}
+
+	// Importing again only reports conflicts and the generated abbreviations are unique
+	plan, err = core.PlanImport("z", writeImportFile(t, fmt.Sprintf("%s|1|1\n", first)), false)
+	if err != nil || len(plan.Added) != 0 || len(plan.Conflicts) != 1 {
+		t.Errorf("Expected only a conflict, got %v (err: %v)", plan, err)
+	}
+
+	third := filepath.Join(dir, "c", "docs")
+	mkdirs(t, third)
+	plan, err = core.PlanImport("z", writeImportFile(t, fmt.Sprintf("%s|1|1\n", third)), false)
+	if err != nil || len(plan.Added) != 1 || plan.Added[0].Abbreviation != "docs-2" {
+		t.Errorf("Expected the abbreviation docs-2, got %v (err: %v)", plan.Added, err)
+	}
+}
+
+func TestImport_GeneratedAbbreviationRules(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "a", "docs"), filepath.Join(dir, "b", "docs")
+	mkdirs(t, first, second)
+	useConfigDir(t, `{"abbreviation_pattern": "^[a-z]+$"}`)
+
+	// "docs-2" doesn't match the pattern, so the second path is skipped
+	plan, err := core.PlanImport("z", writeImportFile(t, fmt.Sprintf("%s|2|1\n%s|1|1\n", first, second)), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Added) != 1 || plan.Added[0].Abbreviation != "docs" {
+		t.Errorf("Expected only the abbreviation docs, got %v", plan.Added)
+	}
+	if len(plan.Skipped) != 1 || plan.Skipped[0].Path != second {
+		t.Errorf("Expected the second path to be skipped, got %v", plan.Skipped)
+	}
+}
@@ -1,9 +1,12 @@
 package tests
 
 import (
+	"fmt"
 	"goto/src/core"
 	"goto/src/utils"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -47,7 +50,10 @@ func TestInitializeConfig(t *testing.T) {
 	msgChan := make(chan core.Message, 100)
 
 	// Run Init
-	err = core.InitializeConfig(msgChan)
+	err = core.InitializeConfig(msgChan, core.InitOptions{Completion: func(shell string, w io.Writer) error {
+		_, err := fmt.Fprintf(w, "# %s completion\n", shell)
+		return err
+	}})
 	if err != nil {
 		t.Errorf("InitializeConfig failed: %v", err)
 	}
@@ -65,4 +71,186 @@ func TestInitializeConfig(t *testing.T) {
 	if _, err := os.Stat(expectedAliasPath); os.IsNotExist(err) {
 		t.Errorf("Alias file not created at %s", expectedAliasPath)
 	}
+
+	// The completion scripts are generated and loaded by alias.sh
+	for _, shell := range core.CompletionShells {
+		completionFile := filepath.Join(tmpHome, ".config", "goto", "goto-run-testing", "completions", "goto."+shell)
+		if content, err := os.ReadFile(completionFile); err != nil || !strings.Contains(string(content), shell+" completion") {
+			t.Errorf("Completion script not created at %s (err: %v)", completionFile, err)
+		}
+	}
+	aliasContent, _ := os.ReadFile(expectedAliasPath)
+	if !strings.Contains(string(aliasContent), "goto.bash") {
+		t.Errorf("alias.sh does not load the completion script: %s", aliasContent)
+	}
+}
+
+func TestDetectShell(t *testing.T) {
+	cases := map[string]string{
+		"/bin/bash":           "bash",
+		"/usr/bin/zsh":        "zsh",
+		"/usr/local/bin/fish": "fish",
+		"/bin/tcsh":           "tcsh",
+		"/bin/csh":            "csh",
+		"/usr/bin/pwsh":       "pwsh",
+		"/bin/ksh":            "ksh",
+		"/bin/dash":           "sh",
+		"/bin/sh":             "sh",
+	}
+	for path, expected := range cases {
+		shell, err := core.DetectShell(path)
+		if err != nil || shell != expected {
+			t.Errorf("DetectShell(%q) = %q, %v; expected %q", path, shell, err, expected)
+		}
+	}
+
+	if _, err := core.DetectShell("/usr/bin/nu"); err == nil {
+		t.Errorf("expected an error for an unsupported shell")
+	}
+}
+
+func TestPrintShellScript(t *testing.T) {
+	expected := map[string]string{
+		"bash": "goto() {",
+		"sh":   "command cd",
+		"zsh":  "add-zsh-hook chpwd _goto_chpwd",
+		"fish": "function goto",
+		"tcsh": "alias goto",
+		"csh":  "if ($_goto_status == 2) chdir",
+		"pwsh": "Set-Location -LiteralPath",
+	}
+
+	for shell, snippet := range expected {
+		var sb strings.Builder
+		if err := core.PrintShellScript(&sb, shell); err != nil {
+			t.Fatalf("PrintShellScript(%s) failed: %v", shell, err)
+		}
+		if !strings.Contains(sb.String(), snippet) {
+			t.Errorf("the %s script doesn't contain %q:\n%s", shell, snippet, sb.String())
+		}
+
+		// The bash script must be valid
+		if bash, err := exec.LookPath("bash"); err == nil && shell == "bash" {
+			check := exec.Command(bash, "-n")
+			check.Stdin = strings.NewReader(sb.String())
+			if out, err := check.CombinedOutput(); err != nil {
+				t.Errorf("the bash script is invalid: %v\n%s", err, out)
+			}
+		}
+	}
+
+	if err := core.PrintShellScript(io.Discard, "nushell"); err == nil {
+		t.Errorf("expected an error for an unsupported shell")
+	}
+}
+
+func TestInitializeConfigFish(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("SHELL", "/bin/bash")
+	utils.SetupConfigFile()
+
+	// The --shell flag takes precedence over $SHELL
+	if err := core.InitializeConfig(nil, core.InitOptions{Shell: "fish"}); err != nil {
+		t.Fatalf("InitializeConfig failed: %v", err)
+	}
+
+	scriptFile := filepath.Join(tmpHome, ".config", "goto", "goto-run-testing", "alias.fish")
+	if content, err := os.ReadFile(scriptFile); err != nil || !strings.Contains(string(content), "function goto") {
+		t.Errorf("the fish script was not created at %s (err: %v)", scriptFile, err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tmpHome, ".config", "fish", "config.fish"))
+	if !strings.Contains(string(content), "source "+scriptFile) {
+		t.Errorf("config.fish does not load the fish script: %s", content)
+	}
+	if _, err := os.Stat(filepath.Join(tmpHome, ".bashrc")); err == nil {
+		t.Errorf(".bashrc must not be touched when the shell is fish")
+	}
+}
+
+func TestInitializeConfigUpdatesBlock(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("SHELL", "/bin/bash")
+	utils.SetupConfigFile()
+
+	scriptFile := filepath.Join(utils.GetConfigDir(), "alias.sh")
+	bashrc := filepath.Join(tmpHome, ".bashrc")
+
+	// A block with an old config dir, a duplicated block and a source command of an older version of init
+	original := "export FOO=1\n" +
+		"# >>> goto >>>\nsource /old/config/goto/alias.sh\n# <<< goto <<<\n" +
+		"export BAR=2\n" +
+		"# >>> goto >>>\nsource /old/config/goto/alias.sh\n# <<< goto <<<\n" +
+		"#Aliases to use goto:\nsource " + scriptFile + "\n"
+	if err := os.WriteFile(bashrc, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := core.InitializeConfig(nil, core.InitOptions{}); err != nil {
+		t.Fatalf("InitializeConfig failed: %v", err)
+	}
+
+	expected := "export FOO=1\n" +
+		"# >>> goto >>>\n#Aliases to use goto:\nsource " + scriptFile + "\n# <<< goto <<<\n" +
+		"export BAR=2\n"
+	content, _ := os.ReadFile(bashrc)
+	if string(content) != expected {
+		t.Errorf("the goto block was not updated in place:\n%s\nexpected:\n%s", content, expected)
+	}
+
+	// The rc file is backed up before the change (keeping its permissions)
+	backup, err := os.ReadFile(bashrc + ".goto-backup")
+	if err != nil || string(backup) != original {
+		t.Errorf("the rc file was not backed up (err: %v): %s", err, backup)
+	}
+	if info, _ := os.Stat(bashrc); info.Mode().Perm() != 0600 {
+		t.Errorf("the permissions of the rc file changed: %v", info.Mode().Perm())
+	}
+
+	// Running init again doesn't change anything
+	if err := core.InitializeConfig(nil, core.InitOptions{}); err != nil {
+		t.Fatalf("InitializeConfig failed: %v", err)
+	}
+	if again, _ := os.ReadFile(bashrc); string(again) != expected {
+		t.Errorf("running init again changed the rc file:\n%s", again)
+	}
+}
+
+func TestInitializeConfigBrokenBlock(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("SHELL", "/bin/bash")
+	utils.SetupConfigFile()
+
+	scriptFile := filepath.Join(utils.GetConfigDir(), "alias.sh")
+	bashrc := filepath.Join(tmpHome, ".bashrc")
+
+	// A block without its end, a source command of an older version of init with other config dir and
+	// a source command of other tool
+	original := "export FOO=1\n" +
+		"# >>> goto >>>\n" +
+		"export BAR=2\n" +
+		"#Aliases to use goto:\nsource \"/old/config/goto/alias.sh\"\n" +
+		"source /opt/tool/alias.sh\n"
+	if err := os.WriteFile(bashrc, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := core.InitializeConfig(nil, core.InitOptions{}); err != nil {
+		t.Fatalf("InitializeConfig failed: %v", err)
+	}
+
+	expected := "export FOO=1\n" +
+		"# >>> goto >>>\n#Aliases to use goto:\nsource " + scriptFile + "\n# <<< goto <<<\n" +
+		"export BAR=2\n" +
+		"source /opt/tool/alias.sh\n"
+	content, _ := os.ReadFile(bashrc)
+	if string(content) != expected {
+		t.Errorf("unexpected rc file:\n%s\nexpected:\n%s", content, expected)
+	}
 }
@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournal_UndoRedo(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	dir := t.TempDir()
+	mkdirs(t, filepath.Join(dir, "a"), filepath.Join(dir, "b"))
+	initial, _ := utils.LoadGPaths(false)
+
+	if _, err := core.Undo(false); err == nil {
+		t.Error("Expected error, there is nothing to undo")
+	}
+
+	if err := core.AddPath(filepath.Join(dir, "a"), "a", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.AddPath(filepath.Join(dir, "b"), "b", false); err != nil {
+		t.Fatal(err)
+	}
+	withBoth, _ := utils.LoadGPaths(false)
+
+	// Delete the wrong gpath and undo it
+	if _, err := core.DeletePath("", "a", -1, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := core.Undo(false); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if gpaths, _ := utils.LoadGPaths(false); !gpath.DiffGPaths(withBoth, gpaths).Empty() {
+		t.Errorf("Expected the deleted gpath back, got %v", gpaths)
+	}
+
+	// Undo the two adds
+	for i := 0; i < 2; i++ {
+		if _, err := core.Undo(false); err != nil {
+			t.Fatalf("Undo failed: %v", err)
+		}
+	}
+	if gpaths, _ := utils.LoadGPaths(false); !gpath.DiffGPaths(initial, gpaths).Empty() {
+		t.Errorf("Expected the initial gpaths, got %v", gpaths)
+	}
+
+	// Redo the first add
+	operation, err := core.Redo(false)
+	if err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	if gpaths, _ := utils.LoadGPaths(false); len(gpaths) != len(initial)+1 {
+		t.Errorf("Expected the first add redone (%s), got %v", operation, gpaths)
+	}
+
+	history, err := core.History(false)
+	if err != nil || len(history) != 3 || history[0].Undone || !history[1].Undone || !history[2].Undone {
+		t.Errorf("Unexpected history: %+v (err: %v)", history, err)
+	}
+
+	// A new change discards the undone changes
+	if err := core.UpdatePath("aa", "", "a", -1, "first", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := core.Redo(false); err == nil {
+		t.Error("Expected error, there is nothing to redo")
+	}
+	if history, _ := core.History(false); len(history) != 2 || len(history[1].Diff.Changed) != 1 {
+		t.Errorf("Unexpected history: %+v", history)
+	}
+}
+
+func TestJournal_Diverged(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	dir := t.TempDir()
+	mkdirs(t, filepath.Join(dir, "a"))
+
+	if err := core.AddPath(filepath.Join(dir, "a"), "a", false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Change the file without goto
+	gpaths, _ := utils.LoadGPaths(false)
+	gpaths[0].Abbreviation = "changed"
+	if err := gpath.SaveGPathsFile(gpaths, utils.GetFilePath(false)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := core.Undo(false); err == nil {
+		t.Error("Expected error, the goto-paths file was changed outside of goto")
+	}
+
+	// The file is not modified
+	if after, _ := utils.LoadGPaths(false); !gpath.DiffGPaths(gpaths, after).Empty() {
+		t.Errorf("The failed undo changed the goto-paths file: %v", after)
+	}
+}
+
+func TestJournal_UndoPromote(t *testing.T) {
+	_, cleanup := resetConfigFile(t, true)
+	defer cleanup()
+	_, cleanupPermanent := resetConfigFile(t, false)
+	defer cleanupPermanent()
+
+	dir := t.TempDir()
+	mkdirs(t, filepath.Join(dir, "a"), filepath.Join(dir, "b"))
+	if err := core.AddPath(filepath.Join(dir, "a"), "a", true); err != nil {
+		t.Fatal(err)
+	}
+	permanent, _ := utils.LoadGPaths(false)
+	temporal, _ := utils.LoadGPaths(true)
+
+	if _, err := core.PromotePath("a"); err != nil {
+		t.Fatal(err)
+	}
+	promoted, _ := utils.LoadGPaths(false)
+
+	// Undoing the change of any of the files reverts the promote in both
+	for _, useTemporal := range []bool{false, true} {
+		if _, err := core.Undo(useTemporal); err != nil {
+			t.Fatalf("Undo failed: %v", err)
+		}
+		if gpaths, _ := utils.LoadGPaths(false); !gpath.DiffGPaths(permanent, gpaths).Empty() {
+			t.Errorf("Expected the goto-paths file before the promote, got %v", gpaths)
+		}
+		if gpaths, _ := utils.LoadGPaths(true); !gpath.DiffGPaths(temporal, gpaths).Empty() {
+			t.Errorf("Expected the temporal file before the promote, got %v", gpaths)
+		}
+
+		if _, err := core.Redo(!useTemporal); err != nil {
+			t.Fatalf("Redo failed: %v", err)
+		}
+		if gpaths, _ := utils.LoadGPaths(false); !gpath.DiffGPaths(promoted, gpaths).Empty() {
+			t.Errorf("Expected the promoted gpath, got %v", gpaths)
+		}
+	}
+
+	// A later change of the temporal file must be undone before the promote
+	if err := core.AddPath(filepath.Join(dir, "b"), "b", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := core.Undo(false); err == nil {
+		t.Error("Expected error, the temporal file was changed after the promote")
+	}
+	if gpaths, _ := utils.LoadGPaths(false); !gpath.DiffGPaths(promoted, gpaths).Empty() {
+		t.Errorf("The failed undo changed the goto-paths file: %v", gpaths)
+	}
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"goto/src/core"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseLayerOrder(t *testing.T) {
+	layers, err := core.ParseLayerOrder(" Permanent, project ")
+	if err != nil || !slices.Equal(layers, []string{core.LayerPermanent, core.LayerProject}) {
+		t.Errorf("unexpected layers %v (err: %v)", layers, err)
+	}
+
+	for _, order := range []string{"temporal,other", "temporal,temporal", ""} {
+		if _, err := core.ParseLayerOrder(order); err == nil {
+			t.Errorf("expected an error for the order %q", order)
+		}
+	}
+
+	t.Setenv(core.RESOLVE_ORDER_ENV_VAR, "")
+	if layers, err := core.GetLayerOrder(); err != nil || !slices.Equal(layers, core.DefaultLayerOrder) {
+		t.Errorf("expected the default order, got %v (err: %v)", layers, err)
+	}
+}
+
+func TestResolveLayers(t *testing.T) {
+	_, cleanup := resetConfigFile(t, true)
+	defer cleanup()
+	_, cleanupPermanent := resetConfigFile(t, false)
+	defer cleanupPermanent()
+
+	dir := t.TempDir()
+	repoDocs, other, tmp := filepath.Join(dir, "docs"), filepath.Join(dir, "other"), filepath.Join(dir, "tmp")
+	mkdirs(t, repoDocs, other, tmp)
+	writeProjectFile(t, dir, `[{"path": "docs", "abbreviation": "docs"}]`)
+	t.Chdir(dir)
+
+	if err := core.AddPath(other, "docs", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.AddPath(tmp, "tmp", true); err != nil {
+		t.Fatal(err)
+	}
+
+	// By default the project layer is before the permanent one, the collision is reported
+	resolution, err := core.Resolve([]string{"docs"}, false, false)
+	if err != nil || resolution.Path != repoDocs || resolution.Layer != core.LayerProject {
+		t.Fatalf("expected %s of the project layer, got %+v (err: %v)", repoDocs, resolution, err)
+	}
+	if c := resolution.Collision; c == nil || len(c.Shadowed) != 1 || c.Shadowed[0].Layer != core.LayerPermanent {
+		t.Errorf("expected the collision with the permanent layer, got %+v", c)
+	}
+
+	// The temporal gpaths are resolved without the temporal flag
+	if resolution, err := core.Resolve([]string{"tmp"}, false, false); err != nil || resolution.Path != tmp || resolution.Layer != core.LayerTemporal {
+		t.Errorf("expected %s of the temporal layer, got %+v (err: %v)", tmp, resolution, err)
+	}
+
+	// The indexes are the ones of the goto-paths file, not the temporal ones
+	home, _ := os.UserHomeDir()
+	if path, err := core.ResolvePath([]string{"2"}, false, false); err != nil || path != other {
+		t.Errorf("expected %s for the index 2, got %s (err: %v)", other, path, err)
+	}
+	if path, err := core.ResolvePath([]string{"2"}, false, true); err != nil || path != tmp {
+		t.Errorf("expected %s for the temporal index 2, got %s (err: %v)", tmp, path, err)
+	}
+	if path, err := core.ResolvePath([]string{"0"}, false, false); err != nil || path != home {
+		t.Errorf("expected %s for the index 0, got %s (err: %v)", home, path, err)
+	}
+
+	// The order can be changed and the layers not listed are not used
+	t.Setenv(core.RESOLVE_ORDER_ENV_VAR, "permanent,project")
+	if resolution, err := core.Resolve([]string{"docs"}, false, false); err != nil || resolution.Path != other || resolution.Collision == nil {
+		t.Errorf("expected %s of the permanent layer, got %+v (err: %v)", other, resolution, err)
+	}
+	if resolution, _ := core.Resolve([]string{"tmp"}, false, false); resolution.Layer == core.LayerTemporal {
+		t.Errorf("the temporal layer was used without being in the order")
+	}
+
+	t.Setenv(core.RESOLVE_ORDER_ENV_VAR, "")
+	layers, err := core.ListLayers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range layers {
+		if l.Layer == core.LayerPermanent && l.Abbreviation == "docs" && l.ShadowedBy != core.LayerProject {
+			t.Errorf("expected the permanent docs shadowed by the project layer, got %+v", l)
+		}
+		if l.Layer == core.LayerProject && l.Index != -1 {
+			t.Errorf("the project docs can't have an index (it isn't listed), got %+v", l)
+		}
+	}
+
+	found, err := core.SearchLayers("", "docs")
+	if err != nil || found.Layer != core.LayerProject || found.ExpandedPath() != repoDocs {
+		t.Errorf("expected the project docs, got %+v (err: %v)", found, err)
+	}
+}
+
+func TestResolveLayers_TemporalDefaults(t *testing.T) {
+	_, cleanup := resetConfigFile(t, true)
+	defer cleanup()
+	_, cleanupPermanent := resetConfigFile(t, false)
+	defer cleanupPermanent()
+
+	// The default "h" of the temporal file must not hide an edited "h" of the goto-paths file,
+	// even if the temporal layer is the first one
+	work := t.TempDir()
+	if err := core.UpdatePath("abbv-path", "", "h", -1, work, false); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, order := range []string{"", "temporal,permanent"} {
+		t.Setenv(core.RESOLVE_ORDER_ENV_VAR, order)
+		resolution, err := core.Resolve([]string{"h"}, false, false)
+		if err != nil || resolution.Path != work || resolution.Collision != nil {
+			t.Errorf("expected %s without collision (order %q), got %+v (err: %v)", work, order, resolution, err)
+		}
+	}
+
+	// The defaults of the temporal file are still used with the temporal flag
+	home, _ := os.UserHomeDir()
+	if path, err := core.ResolvePath([]string{"h"}, false, true); err != nil || path != home {
+		t.Errorf("expected the temporal %s, got %s (err: %v)", home, path, err)
+	}
+}
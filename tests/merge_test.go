@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Helper to set the goto-paths file and write a backup file, it returns the backup file and the directories
+func setupMerge(t *testing.T) (string, []string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	dirs := []string{filepath.Join(dir, "a"), filepath.Join(dir, "b"), filepath.Join(dir, "c")}
+	mkdirs(t, dirs...)
+
+	current := []gpath.GotoPath{
+		{Path: dirs[0], Abbreviation: "a"},
+		{Path: dirs[1], Abbreviation: "shared"},
+	}
+	if err := utils.UpdateGPaths(false, current); err != nil {
+		t.Fatal(err)
+	}
+
+	// The backup changes the abbreviation of "a" and uses "shared" for other path
+	backup := `[
+		{"Path": "` + dirs[0] + `", "Abbreviation": "a-backup"},
+		{"Path": "` + dirs[2] + `", "Abbreviation": "shared"}
+	]`
+	backupFile := filepath.Join(dir, "backup.json")
+	if err := os.WriteFile(backupFile, []byte(backup), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return backupFile, dirs
+}
+
+// Helper to find a gpath by path
+func findByPath(gpaths []gpath.GotoPath, path string) (gpath.GotoPath, bool) {
+	for _, gp := range gpaths {
+		if gp.Path == path {
+			return gp, true
+		}
+	}
+	return gpath.GotoPath{}, false
+}
+
+func TestRestoreMerge_Strategies(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	tests := []struct {
+		strategy string
+		aAbbv    string
+		cAbbv    string // empty if "c" must not be added
+		bAbbv    string // empty if "b" must be removed
+	}{
+		{core.MergeKeepCurrent, "a", "", "shared"},
+		{core.MergeTakeBackup, "a-backup", "shared", ""},
+		{core.MergeRename, "a", "c", "shared"},
+	}
+
+	for _, tt := range tests {
+		backupFile, dirs := setupMerge(t)
+
+		if _, err := core.RestoreGPathsWithOptions(backupFile, core.RestoreOptions{Merge: true, Strategy: tt.strategy}, false); err != nil {
+			t.Errorf("%s: merge failed: %v", tt.strategy, err)
+			continue
+		}
+
+		gpaths, _ := utils.LoadGPaths(false)
+		if a, _ := findByPath(gpaths, dirs[0]); a.Abbreviation != tt.aAbbv {
+			t.Errorf("%s: expected the abbreviation %s for a, got %v", tt.strategy, tt.aAbbv, gpaths)
+		}
+		if c, ok := findByPath(gpaths, dirs[2]); c.Abbreviation != tt.cAbbv || ok != (tt.cAbbv != "") {
+			t.Errorf("%s: expected the abbreviation %q for c, got %v", tt.strategy, tt.cAbbv, gpaths)
+		}
+		if b, ok := findByPath(gpaths, dirs[1]); b.Abbreviation != tt.bAbbv || ok != (tt.bAbbv != "") {
+			t.Errorf("%s: expected the abbreviation %q for b, got %v", tt.strategy, tt.bAbbv, gpaths)
+		}
+	}
+
+	backupFile, _ := setupMerge(t)
+	if _, err := core.RestoreGPathsWithOptions(backupFile, core.RestoreOptions{Merge: true, Strategy: "other"}, false); err == nil {
+		t.Error("Expected error for an invalid strategy")
+	}
+}
+
+func TestRestoreMerge_Interactive(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	backupFile, dirs := setupMerge(t)
+
+	conflicts := 0
+	opts := core.RestoreOptions{
+		Merge:    true,
+		Strategy: core.MergeInteractive,
+		Resolve: func(conflict core.MergeConflict) (string, error) {
+			conflicts++
+			if conflict.Backup.Path == dirs[0] {
+				return core.MergeTakeBackup, nil
+			}
+			return core.MergeKeepCurrent, nil
+		},
+	}
+
+	if _, err := core.RestoreGPathsWithOptions(backupFile, opts, false); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	if conflicts != 2 {
+		t.Errorf("Expected 2 conflicts, got %d", conflicts)
+	}
+
+	gpaths, _ := utils.LoadGPaths(false)
+	if a, _ := findByPath(gpaths, dirs[0]); a.Abbreviation != "a-backup" {
+		t.Errorf("Expected the gpath of the backup for a, got %v", gpaths)
+	}
+}
+
+func TestRestore_DryRun(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	backupFile, dirs := setupMerge(t)
+	before, _ := utils.LoadGPaths(false)
+
+	// Overwrite: "a" changes, "c" is added and "b" is removed
+	diff, err := core.RestoreGPathsWithOptions(backupFile, core.RestoreOptions{DryRun: true}, false)
+	if err != nil {
+		t.Fatalf("Dry-run failed: %v", err)
+	}
+	if len(diff.Added) != 1 || len(diff.Removed) != 1 || diff.Removed[0].Path != dirs[1] || len(diff.Changed) != 1 {
+		t.Errorf("Unexpected diff: %+v", diff)
+	}
+
+	// Merge with rename: only "c" is added (with other abbreviation)
+	diff, err = core.RestoreGPathsWithOptions(backupFile, core.RestoreOptions{Merge: true, Strategy: core.MergeRename, DryRun: true}, false)
+	if err != nil {
+		t.Fatalf("Dry-run failed: %v", err)
+	}
+	if len(diff.Added) != 1 || diff.Added[0].Abbreviation != "c" || len(diff.Removed) != 0 || len(diff.Changed) != 0 {
+		t.Errorf("Unexpected diff: %+v", diff)
+	}
+
+	// Merge taking the backup: "a" changes, "c" is added and "b" is removed (its abbreviation is taken)
+	diff, err = core.RestoreGPathsWithOptions(backupFile, core.RestoreOptions{Merge: true, Strategy: core.MergeTakeBackup, DryRun: true}, false)
+	if err != nil {
+		t.Fatalf("Dry-run failed: %v", err)
+	}
+	if len(diff.Added) != 1 || len(diff.Removed) != 1 || len(diff.Changed) != 1 || diff.Changed[0].After.Abbreviation != "a-backup" {
+		t.Errorf("Unexpected diff: %+v", diff)
+	}
+
+	// Nothing is written
+	after, _ := utils.LoadGPaths(false)
+	if d := gpath.DiffGPaths(before, after); !d.Empty() {
+		t.Errorf("The dry-run changed the goto-paths file: %+v", d)
+	}
+}
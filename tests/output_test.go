@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"goto/src/cmd"
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"strings"
+	"testing"
+
+	"github.com/bytedance/sonic"
+)
+
+func TestValidOutputFormat(t *testing.T) {
+	valid := []string{"text", "json", "ndjson", "tsv", "{{.Path}}", "{{.Index}}: {{.Abbreviation}}"}
+	for _, f := range valid {
+		if err := utils.ValidOutputFormat(f); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", f, err)
+		}
+	}
+
+	invalid := []string{"yaml", "/some/path", "{{.Path"}
+	for _, f := range invalid {
+		if err := utils.ValidOutputFormat(f); err == nil {
+			t.Errorf("Expected %q to be invalid", f)
+		}
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	entries := []utils.OutputEntry{
+		utils.NewOutputEntry(0, gpath.GotoPath{Path: "/a", Abbreviation: "a", Tags: []string{"x", "y"}}),
+		utils.NewOutputEntry(1, gpath.GotoPath{Path: "/b", Abbreviation: "b", Description: "the\tb"}).WithStatus(errors.New("invalid")),
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := utils.WriteOutput(&buf, utils.OutputJSON, entries); err != nil {
+			t.Fatal(err)
+		}
+
+		var got []utils.OutputEntry
+		if err := sonic.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("Invalid JSON: %v\n%s", err, buf.String())
+		}
+		if len(got) != 2 || got[1].Valid == nil || *got[1].Valid || got[1].Error != "invalid" {
+			t.Errorf("Unexpected JSON output: %s", buf.String())
+		}
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := utils.WriteOutput(&buf, utils.OutputNDJSON, entries); err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 || !strings.Contains(lines[0], `"path":"/a"`) {
+			t.Errorf("Unexpected NDJSON output: %s", buf.String())
+		}
+	})
+
+	t.Run("tsv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := utils.WriteOutput(&buf, utils.OutputTSV, entries); err != nil {
+			t.Fatal(err)
+		}
+
+		want := "0\ta\t/a\tx,y\t\t\t\n1\tb\t/b\t\tthe b\tfalse\tinvalid\n"
+		if buf.String() != want {
+			t.Errorf("got %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("template", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := utils.WriteOutput(&buf, "{{.Index}}={{.Path}}", entries); err != nil {
+			t.Fatal(err)
+		}
+
+		if buf.String() != "0=/a\n1=/b\n" {
+			t.Errorf("Unexpected template output: %q", buf.String())
+		}
+	})
+
+	t.Run("empty json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := utils.WriteOutput(&buf, utils.OutputJSON, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.TrimSpace(buf.String()) != "[]" {
+			t.Errorf("Expected an empty array, got %q", buf.String())
+		}
+	})
+}
+
+func TestResolvedEntry(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	gpaths, err := utils.LoadGPaths(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := core.ResolvedEntry(gpaths[0].Path, false)
+	if entry.Index != 0 || entry.Abbreviation != gpaths[0].Abbreviation {
+		t.Errorf("Unexpected entry for a gpath: %+v", entry)
+	}
+
+	dir := t.TempDir()
+	entry = core.ResolvedEntry(dir, false)
+	if entry.Index != -1 || entry.Path != dir {
+		t.Errorf("Unexpected entry for a directory: %+v", entry)
+	}
+}
+
+func TestOutputFlag(t *testing.T) {
+	if cmd.RootCmd.PersistentFlags().Lookup(utils.FlagOutput) == nil {
+		t.Error("RootCmd should have the persistent 'output' flag")
+	}
+}
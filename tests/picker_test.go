@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"goto/src/core"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func pickerItems() []core.PickerItem {
+	return []core.PickerItem{
+		{Label: `0 - "/home/me" - h`, Path: "/home/me"},
+		{Label: `1 - "/home/me/projects/api" - api`, Path: "/home/me/projects/api"},
+		{Label: `[t] 0 - "/home/me/projects/web" - web`, Path: "/home/me/projects/web"},
+	}
+}
+
+func TestPicker_Filter(t *testing.T) {
+	p := core.NewPicker(pickerItems(), "PROJ")
+	if got := len(p.Filtered()); got != 2 {
+		t.Errorf("Expected 2 items filtered by \"PROJ\", got %d", got)
+	}
+
+	p = core.NewPicker(pickerItems(), "proj web")
+	if got := p.Filtered(); len(got) != 1 || got[0].Path != "/home/me/projects/web" {
+		t.Errorf("Expected only the web item, got %v", got)
+	}
+}
+
+func TestPicker_Run(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		err   error
+	}{
+		{"enter selects the first item", "\r", "/home/me", nil},
+		{"typing filters", "web\r", "/home/me/projects/web", nil},
+		{"arrow down moves the cursor", "\x1b[B\r", "/home/me/projects/api", nil},
+		{"arrow down in application mode moves the cursor", "\x1bOB\r", "/home/me/projects/api", nil},
+		{"ctrl-down moves the cursor", "\x1b[1;5B\r", "/home/me/projects/api", nil},
+		{"alt-key is ignored", "\x1bB\r", "/home/me", nil},
+		{"ctrl-n and ctrl-p move the cursor", "\x0e\x0e\x10\r", "/home/me/projects/api", nil},
+		{"backspace removes the filter", "web\x7f\x7f\x7f\r", "/home/me", nil},
+		{"escape cancels", "\x1b", "", core.ErrPickerCancelled},
+		{"ctrl-c cancels", "\x03", "", core.ErrPickerCancelled},
+		{"end of input cancels", "xyz\r", "", core.ErrPickerCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			got, err := core.NewPicker(pickerItems(), "").Run(strings.NewReader(tt.input), &out)
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPicker_LoneEscape(t *testing.T) {
+	// The terminal doesn't end the input after Escape, the picker must not wait for more bytes
+	in, w := io.Pipe()
+	defer w.Close()
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := core.NewPicker(pickerItems(), "").Run(in, io.Discard)
+		result <- err
+	}()
+	if _, err := w.Write([]byte("\x1b")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, core.ErrPickerCancelled) {
+			t.Errorf("got error %v, want %v", err, core.ErrPickerCancelled)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("the picker is blocked after a lone Escape")
+	}
+}
+
+func TestPicker_RenderPreview(t *testing.T) {
+	dir := t.TempDir()
+	mkdirs(t, filepath.Join(dir, "subdir"))
+
+	p := core.NewPicker([]core.PickerItem{{Label: "0 - tmp", Path: dir}}, "")
+
+	var out bytes.Buffer
+	p.Render(&out)
+
+	if !strings.Contains(out.String(), "subdir/") {
+		t.Errorf("Expected the preview of the directory, got %q", out.String())
+	}
+}
+
+func TestPickerItems(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	items, err := core.PickerItems()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	temporal := 0
+	for _, item := range items {
+		if strings.HasPrefix(item.Label, "[t]") {
+			temporal++
+		}
+	}
+	if temporal == 0 || temporal == len(items) {
+		t.Errorf("Expected permanent and temporal items, got %v", items)
+	}
+}
@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GOTO_TEST_WORK", "/srv/work")
+	t.Setenv("GOTO_TEST_EMPTY", "")
+
+	tests := []struct {
+		path     string
+		portable bool
+		expanded string
+	}{
+		{"~", true, home},
+		{"~/projects/api", true, filepath.Join(home, "projects", "api")},
+		{"$GOTO_TEST_WORK/x", true, "/srv/work/x"},
+		{"${GOTO_TEST_WORK}/x/../y", true, "/srv/work/y"},
+		{"$GOTO_TEST_EMPTY/x", true, "$GOTO_TEST_EMPTY/x"},
+		{"$GOTO_TEST_UNSET", true, "$GOTO_TEST_UNSET"},
+		{"~user/x", false, "~user/x"},
+		{"/srv/$GOTO_TEST_WORK", false, "/srv/$GOTO_TEST_WORK"},
+	}
+
+	for _, tt := range tests {
+		if got := gpath.IsPortable(tt.path); got != tt.portable {
+			t.Errorf("IsPortable(%q) = %v, expected %v", tt.path, got, tt.portable)
+		}
+		if got := gpath.ExpandPath(tt.path); got != tt.expanded {
+			t.Errorf("ExpandPath(%q) = %q, expected %q", tt.path, got, tt.expanded)
+		}
+	}
+}
+
+func TestPortablePath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GOTO_TEST_WORK", filepath.Join(home, "work"))
+
+	tests := []struct {
+		path     string
+		vars     []string
+		expected string
+	}{
+		{filepath.Join(home, "docs"), nil, "~/docs"},
+		{home, nil, "~"},
+		{filepath.Join(home, "work", "api"), []string{"GOTO_TEST_WORK"}, "$GOTO_TEST_WORK/api"},
+		{filepath.Join(home, "docs"), []string{"GOTO_TEST_WORK"}, "~/docs"},
+		{filepath.Join(home, "docs"), []string{"HOME"}, "~/docs"},
+		{"/srv/other", []string{"GOTO_TEST_WORK"}, "/srv/other"},
+		{home + "-other", nil, home + "-other"},
+	}
+
+	for _, tt := range tests {
+		if got := gpath.PortablePath(tt.path, tt.vars); got != tt.expected {
+			t.Errorf("PortablePath(%q, %v) = %q, expected %q", tt.path, tt.vars, got, tt.expected)
+		}
+	}
+}
+
+func TestValidStoredPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	mkdirs(t, filepath.Join(home, "docs"))
+
+	if got, err := gpath.ValidStoredPath(" ~/docs/ "); err != nil || got != "~/docs" {
+		t.Errorf("expected ~/docs, got %q (err: %v)", got, err)
+	}
+	if _, err := gpath.ValidStoredPath("~/missing"); err == nil {
+		t.Errorf("expected an error for a portable path that doesn't exist")
+	}
+	if _, err := gpath.ValidStoredPath("$GOTO_TEST_UNSET/docs"); err == nil {
+		t.Errorf("expected an error for an unset environment variable")
+	}
+	if got, err := gpath.ValidStoredPath(filepath.Join(home, "docs")); err != nil || got != filepath.Join(home, "docs") {
+		t.Errorf("expected the absolute path, got %q (err: %v)", got, err)
+	}
+}
+
+func TestAddPortablePath(t *testing.T) {
+	_, cleanup := resetConfigFile(t, true)
+	defer cleanup()
+
+	home := t.TempDir()
+	work := filepath.Join(home, "work")
+	docs, api := filepath.Join(home, "docs"), filepath.Join(work, "api")
+	mkdirs(t, docs, api)
+
+	t.Setenv("HOME", home)
+	t.Setenv("GOTO_TEST_WORK", work)
+
+	if err := core.AddPathWithOptions(docs, "docs", core.AddOptions{Portable: true}, true); err != nil {
+		t.Fatalf("AddPathWithOptions failed: %v", err)
+	}
+	if err := core.AddPathWithOptions(api, "api", core.AddOptions{Portable: true, PortableVars: []string{"GOTO_TEST_WORK"}}, true); err != nil {
+		t.Fatalf("AddPathWithOptions failed: %v", err)
+	}
+
+	// The expansion of a portable path is the same path
+	if err := core.AddPathWithOptions(docs, "docs2", core.AddOptions{}, true); err == nil {
+		t.Errorf("expected an error adding the expansion of a portable path")
+	}
+
+	gpaths, err := utils.ReadGPaths(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored := map[string]string{}
+	for _, gp := range gpaths {
+		stored[gp.Abbreviation] = gp.Path
+	}
+	if stored["docs"] != "~/docs" || stored["api"] != "$GOTO_TEST_WORK/api" {
+		t.Fatalf("the paths were not stored as portable paths: %v", stored)
+	}
+
+	// The paths are expanded when they are resolved
+	if path, err := core.ResolvePath([]string{"api"}, false, true); err != nil || path != api {
+		t.Errorf("expected %s, got %q (err: %v)", api, path, err)
+	}
+
+	// If the environment variable is not set, the path is not resolved (the template is not a path to move)
+	t.Setenv("GOTO_TEST_WORK", "")
+	for _, args := range [][]string{{"api"}, {"api", "sub"}} {
+		if path, err := core.ResolvePath(args, false, true); err == nil || !strings.Contains(err.Error(), "GOTO_TEST_WORK is not set") {
+			t.Errorf("expected an error for the unset variable resolving %v, got %q (err: %v)", args, path, err)
+		}
+	}
+	t.Setenv("GOTO_TEST_WORK", work)
+	if _, gp, err := core.SearchPath(docs, "", true); err != nil || gp.Abbreviation != "docs" {
+		t.Errorf("the portable path was not found by its expansion (err: %v)", err)
+	}
+
+	entry := utils.NewOutputEntry(0, gpath.GotoPath{Path: "~/docs", Abbreviation: "docs"})
+	if entry.Path != "~/docs" || entry.ExpandedPath != docs {
+		t.Errorf("unexpected output entry: %+v", entry)
+	}
+
+	// The expanded path is validated
+	if _, problems, err := core.CheckPaths(true); err != nil || len(problems) != 0 {
+		t.Errorf("expected no problems, got %v (err: %v)", problems, err)
+	}
+
+	t.Setenv("GOTO_TEST_WORK", filepath.Join(home, "moved"))
+	_, problems, err := core.CheckPaths(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 1 || problems[0].GotoPath.Abbreviation != "api" || problems[0].Kind != gpath.ProblemMissing {
+		t.Errorf("expected the missing expanded path of api, got %+v", problems)
+	}
+}
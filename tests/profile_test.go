@@ -0,0 +1,263 @@
+package tests
+
+import (
+	"goto/src/cmd"
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Helper to reset the config directory and select the default profile after the test
+func resetProfiles(t *testing.T) func() {
+	_, cleanup := resetConfigFile(t, false)
+	return func() {
+		cleanup()
+		utils.SetupConfigFile()
+	}
+}
+
+func TestProfiles_CreateSwitchDelete(t *testing.T) {
+	defer resetProfiles(t)()
+
+	if err := core.CreateProfile("work", ""); err != nil {
+		t.Fatalf("CreateProfile failed: %v", err)
+	}
+
+	if err := core.CreateProfile("work", ""); err == nil {
+		t.Error("Expected error creating a profile that already exists")
+	}
+
+	if err := core.CreateProfile("bad name", ""); err == nil {
+		t.Error("Expected error for an invalid profile name")
+	}
+
+	if err := core.SwitchProfile("work"); err != nil {
+		t.Fatalf("SwitchProfile failed: %v", err)
+	}
+
+	if err := utils.UseProfile(""); err != nil {
+		t.Fatalf("UseProfile failed: %v", err)
+	}
+	if utils.GetCurrentProfile() != "work" || utils.GetFilePath(false) != utils.GetProfileFilePath("work") {
+		t.Errorf("Expected the work profile in use, got %s (%s)", utils.GetCurrentProfile(), utils.GetFilePath(false))
+	}
+
+	// The paths are added to the profile in use
+	if err := core.AddPath(".", "workdir", false); err != nil {
+		t.Fatal(err)
+	}
+	defaultGPaths, _ := utils.LoadProfileGPaths(utils.DEFAULT_PROFILE)
+	for _, gp := range defaultGPaths {
+		if gp.Abbreviation == "workdir" {
+			t.Error("The path was added to the default profile")
+		}
+	}
+
+	profiles, err := core.ListProfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 2 || profiles[0].Name != utils.DEFAULT_PROFILE || !profiles[1].Current {
+		t.Errorf("Unexpected profiles: %v", profiles)
+	}
+
+	if err := core.DeleteProfile(utils.DEFAULT_PROFILE); err == nil {
+		t.Error("Expected error deleting the default profile")
+	}
+
+	if err := core.DeleteProfile("work"); err != nil {
+		t.Fatalf("DeleteProfile failed: %v", err)
+	}
+	if _, err := os.Stat(utils.GetProfileFilePath("work")); !os.IsNotExist(err) {
+		t.Error("Expected the profile file to be removed")
+	}
+
+	// The selected profile was deleted, the default is used
+	if err := utils.UseProfile(""); err != nil || utils.GetCurrentProfile() != utils.DEFAULT_PROFILE {
+		t.Errorf("Expected the default profile, got %s (err: %v)", utils.GetCurrentProfile(), err)
+	}
+}
+
+func TestProfiles_SelectedProfileMissing(t *testing.T) {
+	defer resetProfiles(t)()
+
+	if err := core.CreateProfile("work", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.SwitchProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file of the selected profile is removed outside of goto, the default profile is used with a warning
+	if err := os.Remove(utils.GetProfileFilePath("work")); err != nil {
+		t.Fatal(err)
+	}
+	if err := utils.UseProfile(""); err != nil || utils.GetCurrentProfile() != utils.DEFAULT_PROFILE {
+		t.Errorf("Expected the default profile, got %s (err: %v)", utils.GetCurrentProfile(), err)
+	}
+	if !strings.Contains(utils.ProfileWarning(), "\"work\" doesn't exist") {
+		t.Errorf("Expected a warning about the missing profile, got %q", utils.ProfileWarning())
+	}
+
+	if err := core.SwitchProfile(utils.DEFAULT_PROFILE); err != nil {
+		t.Fatal(err)
+	}
+	if err := utils.UseProfile(""); err != nil || utils.ProfileWarning() != "" {
+		t.Errorf("Expected no warning after switching, got %q (err: %v)", utils.ProfileWarning(), err)
+	}
+}
+
+func TestProfiles_EnvVarAndFlagPrecedence(t *testing.T) {
+	defer resetProfiles(t)()
+
+	core.CreateProfile("env", "")
+	core.CreateProfile("switched", "")
+	core.SwitchProfile("switched")
+
+	t.Setenv(utils.PROFILE_ENV_VAR, "env")
+
+	utils.UseProfile("")
+	if utils.GetCurrentProfile() != "env" {
+		t.Errorf("Expected the profile of the environment variable, got %s", utils.GetCurrentProfile())
+	}
+
+	utils.UseProfile(utils.DEFAULT_PROFILE)
+	if utils.GetCurrentProfile() != utils.DEFAULT_PROFILE {
+		t.Errorf("Expected the profile of the flag, got %s", utils.GetCurrentProfile())
+	}
+
+	if err := utils.UseProfile("missing"); err == nil {
+		t.Error("Expected error for a profile that doesn't exist")
+	}
+}
+
+func TestProfiles_Copy(t *testing.T) {
+	defer resetProfiles(t)()
+
+	if err := core.AddPath(".", "copied", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := core.CopyProfile(utils.DEFAULT_PROFILE, "copy"); err != nil {
+		t.Fatalf("CopyProfile failed: %v", err)
+	}
+
+	gpaths, err := utils.LoadProfileGPaths("copy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gpaths[len(gpaths)-1].Abbreviation != "copied" {
+		t.Errorf("Expected the copied paths, got %v", gpaths)
+	}
+
+	if err := core.CopyProfile(utils.DEFAULT_PROFILE, "copy"); err == nil {
+		t.Error("Expected error copying to a profile that already exists")
+	}
+}
+
+func TestProfiles_BaseChain(t *testing.T) {
+	defer resetProfiles(t)()
+
+	baseDir := t.TempDir()
+	if err := core.CreateProfile("base", ""); err != nil {
+		t.Fatal(err)
+	}
+	utils.UseProfile("base")
+	if err := core.AddPath(baseDir, "inherited", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := core.CreateProfile("project", "missing"); err == nil {
+		t.Error("Expected error for a base profile that doesn't exist")
+	}
+	if err := core.CreateProfile("project", "base"); err != nil {
+		t.Fatal(err)
+	}
+	utils.UseProfile("project")
+
+	chain, err := utils.GetProfileChain("project")
+	if err != nil || len(chain) != 2 || chain[1] != "base" {
+		t.Fatalf("Unexpected chain %v (err: %v)", chain, err)
+	}
+
+	got, err := core.ResolvePath([]string{"inherited"}, false, false)
+	if err != nil {
+		t.Fatalf("Expected the path to be resolved in the base profile: %v", err)
+	}
+	if got != baseDir {
+		t.Errorf("got %q, want %q", got, baseDir)
+	}
+
+	if err := core.DeleteProfile("base"); err == nil {
+		t.Error("Expected error deleting the base of other profile")
+	}
+
+	// A cycle in the bases is reported
+	config, _ := utils.LoadProfilesConfig()
+	config.Bases["base"] = "project"
+	utils.UpdateProfilesConfig(config)
+	if _, err := utils.GetProfileChain("project"); err == nil {
+		t.Error("Expected error for a cycle in the bases")
+	}
+}
+
+func TestProfileCmd(t *testing.T) {
+	if cmd.RootCmd.PersistentFlags().Lookup(utils.FlagProfile) == nil {
+		t.Error("RootCmd should have the persistent 'profile' flag")
+	}
+
+	for _, name := range []string{"list", "create", "switch", "copy", "delete"} {
+		if c, _, err := cmd.ProfileCmd.Find([]string{name}); err != nil || c.Name() != name {
+			t.Errorf("ProfileCmd should have the %q subcommand", name)
+		}
+	}
+}
+
+func TestProfiles_DeleteRemovesDerivedFiles(t *testing.T) {
+	defer resetProfiles(t)()
+
+	if err := core.CreateProfile("work", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := utils.UseProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.AddPath(t.TempDir(), "old", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := utils.UseProfile(utils.DEFAULT_PROFILE); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.DeleteProfile("work"); err != nil {
+		t.Fatalf("DeleteProfile failed: %v", err)
+	}
+	file := utils.GetProfileFilePath("work")
+	if matches, _ := filepath.Glob(file + "*"); len(matches) != 0 {
+		t.Errorf("Expected all the files of the profile removed, got %v", matches)
+	}
+
+	// A new profile with the same name doesn't have the journal nor the backups of the deleted one
+	if err := core.CreateProfile("work", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := utils.UseProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	defer utils.UseProfile(utils.DEFAULT_PROFILE)
+
+	before, _ := utils.LoadGPaths(false)
+	if _, err := core.Undo(false); err == nil {
+		t.Error("Expected nothing to undo in the new profile")
+	}
+	if after, _ := utils.LoadGPaths(false); !gpath.DiffGPaths(before, after).Empty() {
+		t.Errorf("The undo changed the new profile: %v", after)
+	}
+	if generations, _ := core.ListBackupGenerations(); len(generations) != 0 {
+		t.Errorf("Expected no automatic backups in the new profile, got %v", generations)
+	}
+}
@@ -0,0 +1,192 @@
+package tests
+
+import (
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Helper to write a project file with the content
+func writeProjectFile(t *testing.T, dir, content string) string {
+	t.Helper()
+	file := filepath.Join(dir, utils.PROJECT_FILE_NAME)
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write project file: %v", err)
+	}
+	return file
+}
+
+func TestProjectFiles_Resolve(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	mkdirs(t, filepath.Join(root, "shared"), filepath.Join(repo, "docs"), filepath.Join(repo, "api", "src"))
+
+	writeProjectFile(t, root, `[{"path": "shared", "abbreviation": "shared"}, {"path": "repo/docs", "abbreviation": "docs"}]`)
+	repoFile := writeProjectFile(t, repo, `[{"path": "./docs", "abbreviation": "docs"}, {"path": "api", "abbreviation": "api"}]`)
+	t.Chdir(filepath.Join(repo, "api", "src"))
+
+	// The relative paths are resolved against the directory of the project file
+	path, err := core.ResolvePath([]string{"api"}, false, false)
+	if err != nil || path != filepath.Join(repo, "api") {
+		t.Errorf("Expected %s, got %s (err: %v)", filepath.Join(repo, "api"), path, err)
+	}
+
+	// The farther project file is also used
+	if path, err := core.ResolvePath([]string{"shared"}, false, false); err != nil || path != filepath.Join(root, "shared") {
+		t.Errorf("Expected %s, got %s (err: %v)", filepath.Join(root, "shared"), path, err)
+	}
+
+	gpaths, err := core.ListResolvablePaths(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The user gpaths keep their indexes and the project gpaths come after them (the nearest first)
+	userGPaths, _ := core.ListPaths(false)
+	if len(gpaths) != len(userGPaths)+3 {
+		t.Fatalf("Expected %d gpaths, got %d: %v", len(userGPaths)+3, len(gpaths), gpaths)
+	}
+	docs := gpaths[len(userGPaths)]
+	if docs.Abbreviation != "docs" || docs.Source != repoFile {
+		t.Errorf("Expected the docs gpath of the nearest project file, got %v (from %s)", docs, docs.Source)
+	}
+
+	// The project gpaths are resolved by index too
+	index := strconv.Itoa(len(userGPaths) + 1)
+	if path, err := core.ResolvePath([]string{index}, false, false); err != nil || path != filepath.Join(repo, "api") {
+		t.Errorf("Expected %s for the index %s, got %s (err: %v)", filepath.Join(repo, "api"), index, path, err)
+	}
+}
+
+func TestProjectFiles_UserWins(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	dir := t.TempDir()
+	mkdirs(t, filepath.Join(dir, "docs"))
+	writeProjectFile(t, dir, `[{"path": "docs", "abbreviation": "h"}]`)
+	t.Chdir(dir)
+
+	// The default goto-paths file has the abbreviation "h" for the home directory, it wins when the
+	// permanent layer is before the project one (by default the project layer is first, see TestResolveLayers)
+	t.Setenv(core.RESOLVE_ORDER_ENV_VAR, "permanent,project")
+	home, _ := os.UserHomeDir()
+	if path, err := core.ResolvePath([]string{"h"}, false, false); err != nil || path != home {
+		t.Errorf("Expected the user gpath %s, got %s (err: %v)", home, path, err)
+	}
+
+	// The listed gpaths never include a project gpath that repeats a user abbreviation
+	gpaths, err := core.ListResolvablePaths(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, gp := range gpaths {
+		if gp.Abbreviation == "h" && gp.Source != "" {
+			t.Errorf("The project gpath h is listed: %v", gp)
+		}
+	}
+}
+
+func TestProjectFiles_EmptyAndInvalid(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	dir := t.TempDir()
+	file := writeProjectFile(t, dir, `[]`)
+	t.Chdir(dir)
+
+	if _, err := core.ListResolvablePaths(false); err != nil {
+		t.Errorf("An empty project file must be valid: %v", err)
+	}
+
+	writeProjectFile(t, dir, `not json`)
+	if _, err := gpath.LoadProjectFile(file); err == nil {
+		t.Error("Expected error loading an invalid project file")
+	}
+}
+
+func TestProjectFiles_BrokenParent(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	parent := t.TempDir()
+	repo := filepath.Join(parent, "repo")
+	mkdirs(t, filepath.Join(repo, "docs"))
+	broken := writeProjectFile(t, parent, `[{"path": "x", "abbreviation": `)
+	writeProjectFile(t, repo, `[{"path": "docs", "abbreviation": "docs"}]`)
+	t.Chdir(repo)
+
+	// The broken file is skipped with a warning, the other layers and project files are still used
+	home, _ := os.UserHomeDir()
+	stderr := captureStderr(func() {
+		if path, err := core.ResolvePath([]string{"h"}, false, false); err != nil || path != home {
+			t.Errorf("Expected %s, got %s (err: %v)", home, path, err)
+		}
+		if path, err := core.ResolvePath([]string{"docs"}, false, false); err != nil || path != filepath.Join(repo, "docs") {
+			t.Errorf("Expected the docs of the project file, got %s (err: %v)", path, err)
+		}
+		if _, err := core.ListResolvablePaths(false); err != nil {
+			t.Errorf("Unexpected error listing the paths: %v", err)
+		}
+	})
+	if !strings.Contains(stderr, "Warning") || !strings.Contains(stderr, broken) {
+		t.Errorf("Expected a warning about %s, got %q", broken, stderr)
+	}
+}
+
+func TestProjectFiles_AddLocal(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	dir := t.TempDir()
+	mkdirs(t, filepath.Join(dir, "docs"), filepath.Join(dir, "sub"))
+	t.Chdir(dir)
+
+	if err := core.AddPathWithOptions("./docs", "docs", core.AddOptions{Local: true}, true); err == nil {
+		t.Error("Expected error adding a local path to the temporal file")
+	}
+
+	// Without a project file, it is created in the working directory
+	if err := core.AddPathWithOptions("./docs", "docs", core.AddOptions{Local: true}, false); err != nil {
+		t.Fatalf("AddPathWithOptions failed: %v", err)
+	}
+
+	// From a subdirectory, the nearest project file is used
+	t.Chdir(filepath.Join(dir, "sub"))
+	if err := core.AddPathWithOptions(".", "sub", core.AddOptions{Local: true, Tags: []string{"x"}}, false); err != nil {
+		t.Fatalf("AddPathWithOptions failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "sub", utils.PROJECT_FILE_NAME)); !os.IsNotExist(err) {
+		t.Error("Expected the path to be added to the existing project file")
+	}
+
+	// The paths are saved relative to the project file
+	data, err := os.ReadFile(filepath.Join(dir, utils.PROJECT_FILE_NAME))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpaths, err := gpath.LoadProjectFile(filepath.Join(dir, utils.PROJECT_FILE_NAME))
+	if err != nil || len(gpaths) != 2 || gpaths[1].Path != filepath.Join(dir, "sub") {
+		t.Errorf("Unexpected project gpaths: %v (err: %v)", gpaths, err)
+	}
+	if strings.Contains(string(data), dir) {
+		t.Errorf("Expected relative paths in the project file, got %s", data)
+	}
+
+	// The user goto-paths file is not modified
+	userGPaths, _ := core.ListPaths(false)
+	for _, gp := range userGPaths {
+		if gp.Abbreviation == "docs" || gp.Abbreviation == "sub" {
+			t.Errorf("The local path was added to the goto-paths file: %v", gp)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"goto/src/utils"
 	"os"
 	"path/filepath"
+	"strconv"
 	"testing"
 )
 
@@ -171,3 +172,49 @@ func TestResolvePath(t *testing.T) {
 		}
 	})
 }
+
+func TestResolvePath_SubPath(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	base := t.TempDir()
+	reports := filepath.Join(base, "2024", "reports")
+	mkdirs(t, reports)
+
+	if err := core.AddPath(base, "docs", false); err != nil {
+		t.Fatal(err)
+	}
+
+	gpathsList, err := utils.LoadGPaths(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"one argument", []string{"docs/2024/reports"}},
+		{"multiple arguments", []string{"docs", "2024", "reports"}},
+		{"mixed arguments", []string{"docs/2024", "reports"}},
+		{"index as first segment", []string{strconv.Itoa(len(gpathsList) - 1), "2024", "reports"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := core.ResolvePath(tt.args, false, false)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != reports {
+				t.Errorf("got %q, want %q", got, reports)
+			}
+		})
+	}
+
+	t.Run("sub-path doesn't exist", func(t *testing.T) {
+		if _, err := core.ResolvePath([]string{"docs", "2025"}, false, false); err == nil {
+			t.Error("Expected error for a sub-path that doesn't exist")
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"errors"
+	"goto/src/gpath"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectSchemaVersion(t *testing.T) {
+	tests := []struct {
+		data    string
+		version int
+		wantErr bool
+	}{
+		{`[{"path": "/a", "abbreviation": "a"}]`, gpath.LegacySchemaVersion, false},
+		{` {"version": 1, "entries": []}`, 1, false},
+		{`{"version": 3, "entries": [], "other": true}`, 3, false},
+		{`{"entries": []}`, 0, true},
+		{`{"version": 0, "entries": []}`, 0, true},
+		{``, 0, true},
+		{`"text"`, 0, true},
+	}
+
+	for _, tt := range tests {
+		version, err := gpath.DetectSchemaVersion([]byte(tt.data))
+		if (err != nil) != tt.wantErr || (err == nil && version != tt.version) {
+			t.Errorf("DetectSchemaVersion(%q) = %d, %v, expected %d (error %v)", tt.data, version, err, tt.version, tt.wantErr)
+		}
+	}
+}
+
+func TestReadGPathsFile_MigratesLegacy(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "goto-paths.json")
+	legacy := `[{"Path": "/a", "Abbreviation": "a", "Tags": ["x"]}]`
+	if err := os.WriteFile(file, []byte(legacy), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file is migrated in memory, reading it doesn't write anything
+	gpaths, err := gpath.ReadGPathsFile(file)
+	if err != nil || len(gpaths) != 1 || gpaths[0].Abbreviation != "a" || len(gpaths[0].Tags) != 1 {
+		t.Fatalf("unexpected gpaths %+v (err: %v)", gpaths, err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != legacy {
+		t.Errorf("the file was changed reading it: %s", data)
+	}
+	if _, err := os.Stat(gpath.PreMigrationBackupPath(file, gpath.LegacySchemaVersion)); !os.IsNotExist(err) {
+		t.Errorf("the copy before the migration must not be written reading the file")
+	}
+
+	// Saving it keeps the original file and writes it in the current version
+	if err := gpath.SaveGPathsFile(gpaths, file); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := os.ReadFile(gpath.PreMigrationBackupPath(file, gpath.LegacySchemaVersion))
+	if err != nil || string(backup) != legacy {
+		t.Errorf("expected the legacy content in the backup, got %q (err: %v)", backup, err)
+	}
+	data, _ := os.ReadFile(file)
+	if version, err := gpath.DetectSchemaVersion(data); err != nil || version != gpath.SchemaVersion {
+		t.Errorf("expected the file migrated to the version %d, got %d (err: %v)\n%s", gpath.SchemaVersion, version, err, data)
+	}
+
+	again, err := gpath.ReadGPathsFile(file)
+	if err != nil || !gpath.DiffGPaths(gpaths, again).Empty() {
+		t.Errorf("the migrated file has other gpaths: %+v (err: %v)", again, err)
+	}
+}
+
+func TestReadGPathsFile_LegacyLastGood(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "goto-paths.json")
+
+	// A corrupted file with a legacy last valid copy, checking the copy doesn't write anything
+	os.WriteFile(file, []byte(`{"version": 1, "entries": [`), 0600)
+	os.WriteFile(gpath.LastGoodCopyPath(file), []byte(`[{"path": "/a", "abbreviation": "a"}]`), 0600)
+
+	var corrupted *gpath.CorruptedFileError
+	if _, err := gpath.ReadGPathsFile(file); !errors.As(err, &corrupted) {
+		t.Errorf("expected a CorruptedFileError, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("expected only the file and its last valid copy, got %v", entries)
+	}
+}
+
+func TestSaveGPathsFile_NewerSchema(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "goto-paths.json")
+	newer := `{"version": 99, "entries": [{"path": "/a", "abbreviation": "a"}], "meta": {}, "future": [1, 2]}`
+	if err := os.WriteFile(file, []byte(newer), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// It can be read, but not overwritten
+	if gpaths, err := gpath.ReadGPathsFile(file); err != nil || len(gpaths) != 1 {
+		t.Errorf("unexpected gpaths %+v (err: %v)", gpaths, err)
+	}
+
+	err := gpath.SaveGPathsFile([]gpath.GotoPath{{Path: "/b", Abbreviation: "b"}}, file)
+	var newerErr *gpath.NewerSchemaError
+	if !errors.As(err, &newerErr) || newerErr.Version != 99 || !strings.Contains(err.Error(), "newer version") {
+		t.Errorf("expected a NewerSchemaError, got %v", err)
+	}
+
+	if data, _ := os.ReadFile(file); string(data) != newer {
+		t.Errorf("the file of the newer version was changed: %s", data)
+	}
+	if _, err := os.Stat(gpath.LastGoodCopyPath(file)); !os.IsNotExist(err) {
+		t.Errorf("the last valid copy must not be written")
+	}
+}
+
+func TestRegisterMigration_Duplicated(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic registering a second migration from the legacy version")
+		}
+	}()
+	gpath.RegisterMigration(gpath.Migration{From: gpath.LegacySchemaVersion})
+}
@@ -0,0 +1,189 @@
+package tests
+
+import (
+	"goto/src/config"
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Helper to use a config file in a temporary directory, the settings of the config directory are loaded after the test
+func useConfigDir(t *testing.T, content string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if content != "" {
+		if err := os.WriteFile(filepath.Join(dir, config.CONFIG_FILE_NAME), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := config.Setup(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.Setup(utils.GetConfigDir()) })
+	return dir
+}
+
+func TestSettings_FileAndEnv(t *testing.T) {
+	useConfigDir(t, `{"navigate_exit_code": 7, "navigate_message": "none"}`)
+
+	settings := config.Get()
+	if settings.ExitCode() != 7 || settings.Message() != "" {
+		t.Errorf("expected the settings of the file, got %+v", settings)
+	}
+
+	// The environment variables override the file, an invalid value is ignored and reported by Check
+	t.Setenv("GOTO_NAVIGATE_EXIT_CODE", "9")
+	t.Setenv("GOTO_NAVIGATE_MESSAGE", "Moved to ")
+	if settings := config.Get(); settings.ExitCode() != 9 || settings.Message() != "Moved to " {
+		t.Errorf("expected the settings of the environment, got %+v", settings)
+	}
+	if err := config.Check(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	t.Setenv("GOTO_NAVIGATE_EXIT_CODE", "3")
+	if config.Get().ExitCode() != 7 {
+		t.Errorf("the invalid exit code of the environment must be ignored")
+	}
+	if err := config.Check(); err == nil || !strings.Contains(err.Error(), "GOTO_NAVIGATE_EXIT_CODE") {
+		t.Errorf("expected an error for the environment variable, got %v", err)
+	}
+
+	setting, err := core.GetSetting("navigate_message")
+	if err != nil || setting.Value != "Moved to " || setting.Source != core.SettingSourceEnv {
+		t.Errorf("unexpected setting %+v (err: %v)", setting, err)
+	}
+}
+
+func TestSettings_InvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, content := range []string{`{bad`, `{"navigate_exit_code": 4}`, `{"resolve_order": "temporal,other"}`} {
+		if err := os.WriteFile(filepath.Join(dir, config.CONFIG_FILE_NAME), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := config.Setup(dir); err == nil {
+			t.Errorf("expected an error loading %s", content)
+		}
+		if config.Check() == nil || config.Get().ExitCode() != config.DefaultNavigateExitCode {
+			t.Errorf("expected the default settings and the error in Check for %s", content)
+		}
+	}
+	_ = config.Setup(utils.GetConfigDir())
+}
+
+func TestSettings_SetAndList(t *testing.T) {
+	dir := useConfigDir(t, "")
+
+	if _, err := core.SetSetting("navigate_message", "Go: "); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := core.SetSetting("resolve_order", "permanent,other"); err == nil {
+		t.Errorf("expected an error for an invalid layer")
+	}
+	if _, err := core.SetSetting("unknown", "1"); err == nil {
+		t.Errorf("expected an error for an unknown setting")
+	}
+
+	// The file is saved and loaded again
+	if err := config.Setup(dir); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := core.ListSettings()
+	if err != nil || len(settings) != len(config.Keys()) {
+		t.Fatalf("unexpected settings %+v (err: %v)", settings, err)
+	}
+	for _, s := range settings {
+		switch {
+		case s.Name == "navigate_message" && (s.Value != "Go: " || s.Source != core.SettingSourceFile):
+			t.Errorf("expected the value of the file, got %+v", s)
+		case s.Name == "navigate_exit_code" && (s.Value != "2" || s.Source != core.SettingSourceDefault):
+			t.Errorf("expected the default value, got %+v", s)
+		}
+	}
+
+	// Without value, the default value is restored
+	if _, err := core.SetSetting("navigate_message", ""); err != nil {
+		t.Fatal(err)
+	}
+	if config.GetFileSettings().NavigateMessage != "" {
+		t.Errorf("expected the default message")
+	}
+}
+
+func TestSettings_Consumers(t *testing.T) {
+	useConfigDir(t, `{"abbreviation_pattern": "^[a-z]+$", "abbreviation_max_length": 5, "navigate_message": "none", "navigate_exit_code": 9}`)
+
+	for abbv, valid := range map[string]bool{"docs": true, "Docs": false, "documents": false} {
+		if _, err := gpath.ValidNewAbbreviation(abbv); (err == nil) != valid {
+			t.Errorf("ValidNewAbbreviation(%q) error = %v, expected valid %v", abbv, err, valid)
+		}
+	}
+
+	// The rules don't apply to the abbreviations already stored
+	if _, err := gpath.ValidAbbreviation("Documents"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	var sb strings.Builder
+	if err := core.PrintShellScript(&sb, "bash"); err != nil {
+		t.Fatal(err)
+	}
+	if script := sb.String(); !strings.Contains(script, "-eq 9") || strings.Contains(script, "echo \"Go to") {
+		t.Errorf("the script doesn't use the settings:\n%s", script)
+	}
+
+	// The backup file is relative to the config directory and uses the profile
+	t.Setenv("GOTO_BACKUP_FILE", "backups/{profile}.json")
+	expected := filepath.Join(utils.GetConfigDir(), "backups", utils.GetCurrentProfile()+".json")
+	if backup := utils.GetDefaultBackupFilePath(); backup != expected {
+		t.Errorf("expected the backup file %s, got %s", expected, backup)
+	}
+}
+
+func TestSettings_DefaultEntries(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOTO_TEST_PROJECTS", dir)
+	t.Setenv("GOTO_DEFAULT_ENTRIES", `[{"path": "$GOTO_TEST_PROJECTS", "abbreviation": "p"}, {"path": "/missing/dir", "abbreviation": "m"}]`)
+
+	file := filepath.Join(t.TempDir(), "goto-paths.json")
+	if err := gpath.CreateGotoPathsFile(file); err != nil {
+		t.Fatal(err)
+	}
+
+	var gpaths []gpath.GotoPath
+	if err := gpath.LoadGPathsFile(&gpaths, file); err != nil {
+		t.Fatal(err)
+	}
+	if len(gpaths) != 1 || gpaths[0].Path != "$GOTO_TEST_PROJECTS" || gpaths[0].ExpandedPath() != dir {
+		t.Errorf("expected only the portable entry, got %+v", gpaths)
+	}
+}
+
+func TestSettings_ConfigCmdRootFlags(t *testing.T) {
+	// "goto config" without a subcommand navigates to the "config" gpath, so it has the flags of the
+	// root command. The path is printed and it exits with status 0, so it runs in a subprocess
+	if os.Getenv("TEST_CONFIG_QUOTES") == "1" {
+		_, cleanup := resetConfigFile(t, false)
+		defer cleanup()
+
+		_ = executeCommand(t, "config", "-q")
+		return
+	}
+
+	c := exec.Command(os.Args[0], "-test.run=^TestSettings_ConfigCmdRootFlags$")
+	c.Env = append(os.Environ(), "TEST_CONFIG_QUOTES=1", utils.TESTING_ENV_VAR+"="+utils.TESTING_ENV_VAR_VALUE)
+	out, err := c.Output()
+	if err != nil {
+		t.Fatalf("goto config -q failed: %v (output: %s)", err, out)
+	}
+
+	if expected := "\"" + utils.GetConfigDir() + "\"\n"; !strings.HasPrefix(string(out), expected) {
+		t.Errorf("Expected the config directory between quotes %q, got %q", expected, out)
+	}
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirStack(t *testing.T) {
+	stack := gpath.DirStack{}
+
+	stack.Push("/a")
+	stack.Push("/b")
+	stack.Push("/b") // The current directory is not repeated
+	stack.Push("/c")
+	if len(stack.Entries) != 3 || stack.Current() != "/c" {
+		t.Fatalf("unexpected stack: %+v", stack)
+	}
+
+	if dir, err := stack.Move(-2); err != nil || dir != "/a" {
+		t.Errorf("expected to go back to /a, got %q (err: %v)", dir, err)
+	}
+	if _, err := stack.Move(-1); err == nil {
+		t.Errorf("expected an error going back from the first directory")
+	}
+	if dir, err := stack.Move(1); err != nil || dir != "/b" {
+		t.Errorf("expected to go forward to /b, got %q (err: %v)", dir, err)
+	}
+
+	// Pushing discards the forward directories
+	stack.Push("/d")
+	if len(stack.Entries) != 3 || stack.Entries[2] != "/d" {
+		t.Errorf("the forward directories were not discarded: %+v", stack)
+	}
+	if _, err := stack.Move(1); err == nil {
+		t.Errorf("expected an error going forward from the last directory")
+	}
+
+	if dir, err := stack.Jump(0); err != nil || dir != "/a" {
+		t.Errorf("expected to jump to /a, got %q (err: %v)", dir, err)
+	}
+
+	// The oldest directories are removed
+	for i := 0; i < gpath.StackMaxEntries+10; i++ {
+		stack.Push(filepath.Join("/dir", string(rune('a'+i%26)), string(rune('0'+i/26))))
+	}
+	if len(stack.Entries) != gpath.StackMaxEntries || stack.Position != gpath.StackMaxEntries-1 {
+		t.Errorf("the stack has %d entries (position %d), expected %d", len(stack.Entries), stack.Position, gpath.StackMaxEntries)
+	}
+}
+
+func TestSessionStack(t *testing.T) {
+	_, cleanup := resetConfigFile(t, true)
+	defer cleanup()
+
+	base := t.TempDir()
+	a, b, c := filepath.Join(base, "a"), filepath.Join(base, "b"), filepath.Join(base, "c")
+	mkdirs(t, a, b, c)
+
+	t.Setenv(utils.SESSION_ENV_VAR, "test-session")
+	t.Chdir(b)
+
+	if err := core.PushStack(a, b); err != nil {
+		t.Fatalf("PushStack failed: %v", err)
+	}
+	if err := core.PushStack(b, c); err != nil {
+		t.Fatalf("PushStack failed: %v", err)
+	}
+
+	// Without saving, the stack doesn't change
+	if dir, err := core.MoveStack(-2, false); err != nil || dir != b {
+		t.Errorf("expected %s, got %q (err: %v)", b, dir, err)
+	}
+	if stack, _ := core.GetStack(); len(stack.Entries) != 3 || stack.Current() != c {
+		t.Errorf("the stack changed without saving: %+v", stack)
+	}
+
+	// The working directory (b) is not the current one of the stack (c), so it is pushed before moving
+	if dir, err := core.MoveStack(-1, true); err != nil || dir != c {
+		t.Errorf("expected %s, got %q (err: %v)", c, dir, err)
+	}
+
+	stack, err := core.GetStack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stack.Entries) != 4 || stack.Current() != c {
+		t.Errorf("unexpected stack: %+v", stack)
+	}
+
+	// Other sessions have their own stack
+	t.Setenv(utils.SESSION_ENV_VAR, "other-session")
+	if stack, _ := core.GetStack(); len(stack.Entries) != 0 {
+		t.Errorf("the stack of other session is not empty: %+v", stack)
+	}
+
+	// An invalid session id is rejected
+	t.Setenv(utils.SESSION_ENV_VAR, "../escape")
+	if _, err := core.GetStack(); err == nil {
+		t.Errorf("expected an error for an invalid session id")
+	}
+
+	// A removed directory can't be visited
+	t.Setenv(utils.SESSION_ENV_VAR, "test-session")
+	if err := os.Remove(a); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := core.JumpStack(0, true); err == nil {
+		t.Errorf("expected an error jumping to a removed directory")
+	}
+}
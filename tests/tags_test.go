@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bytedance/sonic"
+)
+
+func TestValidTags(t *testing.T) {
+	got, err := gpath.ValidTags([]string{" acme ", "api", "ACME"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] != "acme" || got[1] != "api" {
+		t.Errorf("Expected [acme api], got %v", got)
+	}
+
+	invalid := [][]string{{""}, {"  "}, {"with space"}, {"with,comma"}}
+	for _, tags := range invalid {
+		if _, err := gpath.ValidTags(tags); err == nil {
+			t.Errorf("Expected error for tags %q", tags)
+		}
+	}
+}
+
+func TestFilterByTags(t *testing.T) {
+	gpaths := []gpath.GotoPath{
+		{Path: "/a", Abbreviation: "a", Tags: []string{"acme", "api"}},
+		{Path: "/b", Abbreviation: "b", Tags: []string{"acme", "web"}},
+		{Path: "/c", Abbreviation: "c"},
+	}
+
+	if got := gpath.FilterByTags(gpaths, []string{"acme", "api"}, true); len(got) != 1 || got[0] != 0 {
+		t.Errorf("AND filter: expected [0], got %v", got)
+	}
+
+	if got := gpath.FilterByTags(gpaths, []string{"api", "WEB"}, false); len(got) != 2 || got[0] != 0 || got[1] != 1 {
+		t.Errorf("OR filter: expected [0 1], got %v", got)
+	}
+
+	if got := gpath.FilterByTags(gpaths, []string{"none"}, false); len(got) != 0 {
+		t.Errorf("Expected no matches, got %v", got)
+	}
+}
+
+func TestGotoPathString_TagsAndDescription(t *testing.T) {
+	gp := gpath.GotoPath{Path: "/a", Abbreviation: "a"}
+	if got := gp.String(); got != `"/a" - a` {
+		t.Errorf("Unexpected string without tags: %s", got)
+	}
+
+	gp.Tags = []string{"acme", "api"}
+	gp.Description = "The API"
+	if got := gp.String(); got != `"/a" - a [acme, api] - The API` {
+		t.Errorf("Unexpected string with tags: %s", got)
+	}
+}
+
+func TestAddPathWithOptions(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	opts := core.AddOptions{Tags: []string{"acme", "api"}, Description: " ACME API "}
+	if err := core.AddPathWithOptions(".", "acme-api", opts, false); err != nil {
+		t.Fatalf("Failed to add path: %v", err)
+	}
+
+	indexes, gpaths, err := core.SearchByTags([]string{"acme"}, true, false)
+	if err != nil {
+		t.Fatalf("SearchByTags failed: %v", err)
+	}
+	if len(gpaths) != 1 || gpaths[0].Abbreviation != "acme-api" || gpaths[0].Description != "ACME API" {
+		t.Errorf("Unexpected search result: %v", gpaths)
+	}
+
+	all, _ := utils.LoadGPaths(false)
+	if indexes[0] != len(all)-1 {
+		t.Errorf("Expected index %d, got %d", len(all)-1, indexes[0])
+	}
+
+	if _, _, err := core.SearchByTags([]string{"none"}, true, false); err == nil {
+		t.Error("Expected error when no path has the tags")
+	}
+
+	if err := core.AddPathWithOptions(t.TempDir(), "bad", core.AddOptions{Tags: []string{"a b"}}, false); err == nil {
+		t.Error("Expected error for invalid tags")
+	}
+}
+
+func TestUpdatePath_TagsAndDescription(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	if err := core.AddPath(".", "p1", false); err != nil {
+		t.Fatal(err)
+	}
+	cwd, _ := os.Getwd()
+
+	if err := core.UpdatePath("abbv-tags", "", "p1", -1, "acme, api", false); err != nil {
+		t.Fatalf("Update tags failed: %v", err)
+	}
+	if err := core.UpdatePath("pd", cwd, "", -1, "Current dir", false); err != nil {
+		t.Fatalf("Update description failed: %v", err)
+	}
+
+	_, gp, err := core.SearchPath("", "p1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(gp.Tags, ",") != "acme,api" || gp.Description != "Current dir" {
+		t.Errorf("Unexpected tags/description: %v / %q", gp.Tags, gp.Description)
+	}
+
+	// An empty value removes the tags
+	if err := core.UpdatePath("it", "", "", 0, "", false); err != nil {
+		t.Fatalf("Update tags by index failed: %v", err)
+	}
+
+	if err := core.UpdatePath("at", "", "notfound", -1, "x", false); err == nil {
+		t.Error("Expected error for non-existent abbreviation")
+	}
+}
+
+func TestLoadGPaths_WithoutTags(t *testing.T) {
+	_, cleanup := resetConfigFile(t, false)
+	defer cleanup()
+
+	// Old files without tags and description must load, and be saved without them
+	cwd, _ := os.Getwd()
+	content := `[{"path":"` + cwd + `","abbreviation":"old"}]`
+	if err := os.WriteFile(utils.GetFilePath(false), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	gpaths, err := utils.LoadGPaths(false)
+	if err != nil {
+		t.Fatalf("Failed to load old file: %v", err)
+	}
+	if len(gpaths) != 1 || gpaths[0].Tags != nil || gpaths[0].Description != "" {
+		t.Errorf("Unexpected gpaths: %v", gpaths)
+	}
+
+	out, err := sonic.Marshal(gpaths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), "tags") || strings.Contains(string(out), "description") {
+		t.Errorf("Expected empty fields to be omitted, got %s", out)
+	}
+}
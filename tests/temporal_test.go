@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"goto/src/core"
+	"goto/src/gpath"
+	"goto/src/utils"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSplitTemporalGPaths(t *testing.T) {
+	now := time.Now()
+	gpaths := []gpath.GotoPath{
+		{Path: "/a", Abbreviation: "a"},
+		{Path: "/b", Abbreviation: "b", ExpiresAt: now.Add(-time.Minute).Unix()},
+		{Path: "/c", Abbreviation: "c", ExpiresAt: now.Add(time.Minute).Unix(), Session: "s1"},
+		{Path: "/d", Abbreviation: "d", Session: "s2"},
+		{Path: "/e", Abbreviation: "e", ExpiresAt: now.Add(-time.Minute).Unix(), Session: "s2"},
+	}
+
+	visible, hidden := gpath.SplitTemporalGPaths(gpaths, "s1", now)
+	if len(visible) != 2 || visible[0].Abbreviation != "a" || visible[1].Abbreviation != "c" {
+		t.Errorf("unexpected visible gpaths: %+v", visible)
+	}
+	if len(hidden) != 1 || hidden[0].Abbreviation != "d" {
+		t.Errorf("unexpected hidden gpaths: %+v", hidden)
+	}
+
+	if gp := gpaths[2].Permanent(); gp.ExpiresAt != 0 || gp.Session != "" {
+		t.Errorf("the permanent gpath keeps its lifetime: %+v", gp)
+	}
+}
+
+func TestCheckRepeatedItemsSessions(t *testing.T) {
+	// The same gpath in different sessions is valid
+	gpaths := []gpath.GotoPath{
+		{Path: "/a", Abbreviation: "a"},
+		{Path: "/b", Abbreviation: "b", Session: "s1"},
+		{Path: "/b", Abbreviation: "b", Session: "s2"},
+	}
+	if err := gpath.CheckRepeatedItems(gpaths); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// But not in the same session or in all the sessions
+	repeated := [][]gpath.GotoPath{
+		append(gpaths, gpath.GotoPath{Path: "/c", Abbreviation: "b", Session: "s1"}),
+		append(gpaths, gpath.GotoPath{Path: "/a", Abbreviation: "c", Session: "s1"}),
+		append([]gpath.GotoPath{{Path: "/c", Abbreviation: "a", Session: "s1"}}, gpaths...),
+	}
+	for _, r := range repeated {
+		if err := gpath.CheckRepeatedItems(r); err == nil {
+			t.Errorf("expected an error for the repeated gpaths %+v", r)
+		}
+	}
+}
+
+func TestTemporalLifetime(t *testing.T) {
+	_, cleanup := resetConfigFile(t, true)
+	defer cleanup()
+	_, cleanupPermanent := resetConfigFile(t, false)
+	defer cleanupPermanent()
+
+	base := t.TempDir()
+	a, b := filepath.Join(base, "a"), filepath.Join(base, "b")
+	mkdirs(t, a, b)
+
+	if err := core.AddPathWithOptions(a, "a", core.AddOptions{TTL: time.Hour}, false); err == nil {
+		t.Errorf("expected an error adding a permanent path with a time to live")
+	}
+
+	t.Setenv(utils.SESSION_ENV_VAR, "s1")
+	if err := core.AddPathWithOptions(a, "a", core.AddOptions{TTL: time.Hour}, true); err != nil {
+		t.Fatalf("AddPathWithOptions failed: %v", err)
+	}
+	if err := core.AddPathWithOptions(b, "b", core.AddOptions{Session: true}, true); err != nil {
+		t.Fatalf("AddPathWithOptions failed: %v", err)
+	}
+
+	// Other session doesn't see the gpath of s1 and can add its own
+	t.Setenv(utils.SESSION_ENV_VAR, "s2")
+	if _, _, err := core.SearchPath("", "b", true); err == nil {
+		t.Errorf("the gpath of other session is visible")
+	}
+	if err := core.AddPathWithOptions(b, "b", core.AddOptions{Session: true}, true); err != nil {
+		t.Fatalf("AddPathWithOptions failed: %v", err)
+	}
+
+	// Promote the gpath of s1, the one of s2 is kept
+	t.Setenv(utils.SESSION_ENV_VAR, "s1")
+	promoted, err := core.PromotePath("b")
+	if err != nil {
+		t.Fatalf("PromotePath failed: %v", err)
+	}
+	if promoted.Session != "" || promoted.ExpiresAt != 0 {
+		t.Errorf("the promoted gpath keeps its lifetime: %+v", promoted)
+	}
+	if _, gp, err := core.SearchPath("", "b", false); err != nil || gp.Session != "" {
+		t.Errorf("the gpath was not promoted (err: %v)", err)
+	}
+	if _, _, err := core.SearchPath("", "b", true); err == nil {
+		t.Errorf("the promoted gpath is still in the temporal file")
+	}
+
+	t.Setenv(utils.SESSION_ENV_VAR, "s2")
+	if _, _, err := core.SearchPath("", "b", true); err != nil {
+		t.Errorf("the gpath of other session was removed: %v", err)
+	}
+
+	// It can't be demoted to all the sessions (s2 has the same gpath), but it can to the session
+	t.Setenv(utils.SESSION_ENV_VAR, "s1")
+	if _, err := core.DemotePath("b", 0, false); err == nil {
+		t.Errorf("expected an error demoting a gpath repeated in other session")
+	}
+	demoted, err := core.DemotePath("b", time.Minute, true)
+	if err != nil {
+		t.Fatalf("DemotePath failed: %v", err)
+	}
+	if demoted.Session != "s1" || demoted.ExpiresAt == 0 {
+		t.Errorf("the demoted gpath doesn't have its lifetime: %+v", demoted)
+	}
+
+	// The expired gpaths are not loaded and they are removed when the file is saved
+	gpaths, err := utils.LoadGPaths(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range gpaths {
+		if gpaths[i].Abbreviation == "a" {
+			gpaths[i].ExpiresAt = time.Now().Add(-time.Second).Unix()
+		}
+	}
+	if err := utils.UpdateGPaths(true, gpaths); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := core.SearchPath("", "a", true); err == nil {
+		t.Errorf("the expired gpath is visible")
+	}
+
+	if err := core.AddPath(a, "a2", true); err != nil {
+		t.Fatalf("AddPath failed: %v", err)
+	}
+	stored, err := gpath.ReadGPathsFile(utils.GetFilePath(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := map[string]int{}
+	for _, gp := range stored {
+		if gp.Abbreviation == "a" {
+			t.Errorf("the expired gpath was not removed from the file")
+		}
+		sessions[gp.Session]++
+	}
+	if sessions["s1"] != 1 || sessions["s2"] != 1 {
+		t.Errorf("unexpected gpaths of the sessions: %v", sessions)
+	}
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"goto/src/core"
+	"goto/src/utils"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupInstall initializes goto for bash in a temporal home and returns the home
+func setupInstall(t *testing.T) string {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("SHELL", "/bin/bash")
+	utils.SetupConfigFile()
+
+	if err := os.WriteFile(filepath.Join(home, ".bashrc"), []byte("export FOO=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := core.InitializeConfig(nil, core.InitOptions{}); err != nil {
+		t.Fatalf("InitializeConfig failed: %v", err)
+	}
+	return home
+}
+
+func TestUninstall(t *testing.T) {
+	home := setupInstall(t)
+	configDir := utils.GetConfigDir()
+
+	// A source command written by an older version of init (without the markers)
+	zshrc := filepath.Join(home, ".zshrc")
+	legacy := "export BAR=1\n\n#Aliases to use goto:\nsource " + filepath.Join(configDir, "alias.sh") + "\nalias ll='ls -l'\n"
+	if err := os.WriteFile(zshrc, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Dry run: nothing is changed
+	actions, err := core.Uninstall(core.UninstallOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Uninstall failed: %v", err)
+	}
+	if len(actions) != 5 {
+		t.Errorf("expected 5 actions (bashrc, zshrc, alias.sh, goto.bin and the backup of bashrc), got %v", actions)
+	}
+	if _, err := os.Stat(filepath.Join(configDir, "alias.sh")); err != nil {
+		t.Errorf("the dry run removed alias.sh")
+	}
+
+	export := filepath.Join(home, "exported.json")
+	if _, err := core.Uninstall(core.UninstallOptions{Export: export}); err != nil {
+		t.Fatalf("Uninstall failed: %v", err)
+	}
+
+	if content, _ := os.ReadFile(filepath.Join(home, ".bashrc")); string(content) != "export FOO=1\n" {
+		t.Errorf("the goto block was not removed from .bashrc: %q", content)
+	}
+	if content, _ := os.ReadFile(zshrc); string(content) != "export BAR=1\nalias ll='ls -l'\n" {
+		t.Errorf("the legacy source command was not removed from .zshrc: %q", content)
+	}
+
+	for _, file := range []string{"alias.sh", "goto.bin"} {
+		if _, err := os.Stat(filepath.Join(configDir, file)); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed", file)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(home, ".bashrc.goto-backup")); !os.IsNotExist(err) {
+		t.Errorf("the backup of .bashrc made by init was not removed")
+	}
+
+	// The goto-paths are kept
+	if _, err := os.Stat(utils.GetFilePath(false)); err != nil {
+		t.Errorf("the goto-paths file was removed without --purge")
+	}
+	if _, err := os.Stat(export); err != nil {
+		t.Errorf("the goto-paths were not exported: %v", err)
+	}
+
+	// Nothing left to uninstall
+	if actions, _ := core.Uninstall(core.UninstallOptions{}); len(actions) != 0 {
+		t.Errorf("expected nothing to uninstall, got %v", actions)
+	}
+}
+
+func TestUninstallPurge(t *testing.T) {
+	setupInstall(t)
+	configDir := utils.GetConfigDir()
+	temporalDir := filepath.Dir(utils.GetFilePath(true))
+
+	if _, err := core.Uninstall(core.UninstallOptions{Purge: true}); err != nil {
+		t.Fatalf("Uninstall failed: %v", err)
+	}
+
+	for _, dir := range []string{configDir, temporalDir} {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed with --purge", dir)
+		}
+	}
+}
+
+func TestUninstallExportFails(t *testing.T) {
+	home := setupInstall(t)
+	bashrc, _ := os.ReadFile(filepath.Join(home, ".bashrc"))
+
+	// The export refuses to overwrite a file, so nothing is uninstalled
+	export := filepath.Join(home, "exported.json")
+	if err := os.WriteFile(export, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := core.Uninstall(core.UninstallOptions{Export: export}); err == nil {
+		t.Fatal("expected an error exporting to an existing file")
+	}
+
+	if content, _ := os.ReadFile(filepath.Join(home, ".bashrc")); string(content) != string(bashrc) {
+		t.Errorf("the rc file was changed after the failed export: %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(utils.GetConfigDir(), "alias.sh")); err != nil {
+		t.Errorf("alias.sh was removed after the failed export")
+	}
+}